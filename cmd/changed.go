@@ -0,0 +1,84 @@
+// Config file selection
+// Expands config globs and finds config files changed in git
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// configPaths expands a config path that may be a glob pattern
+// A plain path is returned as-is so a missing file is reported by the loader
+func configPaths(pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config pattern '%s': %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config files match '%s'", pattern)
+	}
+	return paths, nil
+}
+
+// changedFiles returns the paths that differ from the given git revision
+// Returns an error if git is unavailable, not in a repository, or the revision is invalid
+func changedFiles(rev string, paths []string) ([]string, error) {
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root = strings.TrimSpace(root)
+
+	args := append([]string{"diff", "--name-only", "-z", rev, "--"}, paths...)
+	out, err := gitOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// git reports paths relative to the repository root
+	changed := make(map[string]bool)
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			changed[filepath.Join(root, name)] = true
+		}
+	}
+
+	var result []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		// Resolve symlinks so paths compare equal to the repository root git reports
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		if changed[abs] {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// gitOutput runs git and returns its stdout, including stderr in errors
+func gitOutput(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
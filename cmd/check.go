@@ -4,12 +4,15 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/config"
 	"github.com/r1ckyIn/healthcheck-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -17,17 +20,39 @@ import (
 // Check command flags
 var (
 	checkTimeout        time.Duration
-	checkExpectedStatus int
+	checkExpectedStatus []string
 	checkHeaders        []string
 	checkInsecure       bool
 	checkOutput         string
+	checkTimingOnly     bool
+	checkDumpRequest    bool
+	checkAddCACert      string
+	checkCACert         string
+	checkClientCert     string
+	checkClientKey      string
+	checkProxy          string
+	checkResolve        []string
+	checkCompleteChain  bool
+	checkMaxHeaderBytes int64
+	checkMaxRedirects   int
+	checkSaveConfig     string
+	checkProbeDNS       bool
+	checkMethod         string
+	checkData           string
+	checkDataFile       string
+	checkBodyContains   string
+	checkAssertHeaders  []string
+	checkShowCert       bool
+	checkOCSP           bool
 )
 
 // checkCmd is the check subcommand
 var checkCmd = &cobra.Command{
-	Use:   "check <url>",
-	Short: "Check health of a single URL",
-	Long: `Check the health status of a single HTTP endpoint.
+	Use:   "check <url>...",
+	Short: "Check health of one or more URLs",
+	Long: `Check the health status of one or more HTTP endpoints.
+
+Several URLs are checked concurrently and reported like a batch run.
 
 The endpoint is considered healthy if:
   - Connection is established successfully
@@ -41,15 +66,66 @@ Examples:
   # With custom timeout
   healthcheck check https://api.example.com/health --timeout 10s
 
+  # Accept either of several status codes
+  healthcheck check https://api.example.com/health -s 200,204
+
+  # Accept any success status
+  healthcheck check https://api.example.com/health -s 2xx
+
+  # Fail when a draining load balancer answers 200 "DOWN"
+  healthcheck check https://web.example.com/status --body-contains UP
+
+  # Require the CDN to serve from cache
+  healthcheck check https://cdn.example.com/app.js --assert-header "X-Cache: HIT"
+
+  # Readiness probe that only answers HEAD
+  healthcheck check https://api.example.com/ready -X HEAD
+
+  # Send a JSON payload kept in source control (POST unless -X is given)
+  healthcheck check https://search.example.com/query -H "Content-Type: application/json" --data-file payload.json
+
   # With authentication header
   healthcheck check https://api.example.com/health -H "Authorization: Bearer token123"
 
   # Skip SSL verification (for self-signed certs)
   healthcheck check https://internal.example.com/health --insecure
 
+  # Trust a private CA in addition to the system trust store
+  healthcheck check https://internal.example.com/health --add-cacert internal-ca.pem
+
+  # Trust only an internal CA bundle instead of the system trust store
+  healthcheck check https://internal.example.com/health --cacert internal-ca.pem
+
+  # Authenticate with a client certificate (mutual TLS)
+  healthcheck check https://partner.example.com/health --cert client.crt --key client.key
+
+  # Check the certificate has not been revoked
+  healthcheck check https://example.com --check-ocsp
+
+  # Check one pod directly while sending the public Host header and SNI
+  healthcheck check https://api.example.com/health --resolve api.example.com:443:10.0.3.12
+
+  # Check through a proxy
+  healthcheck check https://example.com/health --proxy http://proxy.corp.example.com:3128
+
   # JSON output
-  healthcheck check https://api.example.com/health -o json`,
-	Args: cobra.ExactArgs(1),
+  healthcheck check https://api.example.com/health -o json
+
+  # Tell DNS failures apart from HTTP failures and see which IPs the host resolves to
+  healthcheck check https://api.example.com/health --probe-dns -o json
+
+  # Audit the certificate a host serves: subject, issuer, SANs and expiry
+  healthcheck check https://api.example.com/health --show-cert -o json
+
+  # Print the outgoing request to stderr (secrets masked)
+  healthcheck check https://api.example.com/health -H "Authorization: Bearer token123" --dump-request
+
+  # Probe several URLs and save what worked as a starting config
+  healthcheck check https://api.example.com/health https://example.com/login --save-config endpoints.yaml
+
+  # Latency in milliseconds only, for scripts
+  LAT=$(healthcheck check https://api.example.com/health --timing-only)`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runCheck,
 }
 
@@ -59,23 +135,88 @@ func init() {
 	// Define flags
 	checkCmd.Flags().DurationVarP(&checkTimeout, "timeout", "t", 5*time.Second,
 		"Request timeout (e.g., 5s, 10s, 1m)")
-	checkCmd.Flags().IntVarP(&checkExpectedStatus, "expected-status", "s", 200,
-		"Expected HTTP status code")
+	checkCmd.Flags().StringSliceVarP(&checkExpectedStatus, "expected-status", "s", []string{"200"},
+		"Expected HTTP status code, class or range, or several of them (e.g. 200,204 or 2xx or 200-299)")
+	checkCmd.Flags().StringVarP(&checkMethod, "method", "X", "GET",
+		"HTTP request method ("+strings.Join(checker.Methods, ", ")+")")
+	checkCmd.Flags().StringVarP(&checkData, "data", "d", "",
+		"Request payload (implies POST unless --method is set)")
+	checkCmd.Flags().StringVar(&checkDataFile, "data-file", "",
+		"Read the request payload from a file (implies POST unless --method is set)")
+	checkCmd.Flags().StringVar(&checkBodyContains, "body-contains", "",
+		"Require the response body to contain this substring (body read up to 1 MiB)")
+	checkCmd.Flags().StringArrayVar(&checkAssertHeaders, "assert-header", nil,
+		"Require a response header, as 'Name: value' to require that value or 'Name' for presence (can be used multiple times)")
 	checkCmd.Flags().StringArrayVarP(&checkHeaders, "header", "H", nil,
 		"Custom header (can be used multiple times, format: 'Key: Value')")
 	checkCmd.Flags().BoolVarP(&checkInsecure, "insecure", "k", false,
 		"Skip SSL certificate verification")
+	checkCmd.Flags().BoolVar(&checkProbeDNS, "probe-dns", false,
+		"Resolve the host before the HTTP request, failing early on DNS errors and reporting resolved IPs in JSON")
+	checkCmd.Flags().StringVar(&checkAddCACert, "add-cacert", "",
+		"PEM file of CA certificates to trust in addition to the system trust store")
+	checkCmd.Flags().StringVar(&checkCACert, "cacert", "",
+		"PEM file of CA certificates to trust instead of the system trust store")
+	checkCmd.Flags().StringVar(&checkClientCert, "cert", "",
+		"PEM client certificate for mutual TLS, used with --key")
+	checkCmd.Flags().StringVar(&checkClientKey, "key", "",
+		"PEM private key of the --cert client certificate")
+	checkCmd.Flags().StringVar(&checkProxy, "proxy", "",
+		"Proxy URL, e.g. http://proxy:3128 or socks5://bastion:1080 (default HTTP_PROXY/HTTPS_PROXY)")
+	checkCmd.Flags().StringArrayVar(&checkResolve, "resolve", nil,
+		"Connect to addr for host:port while keeping the host for Host and SNI, as host:port:addr (can be used multiple times)")
+	checkCmd.Flags().BoolVar(&checkCompleteChain, "require-complete-chain", false,
+		"Fail when the server omits intermediate certificates, even if they are in the trust store")
+	checkCmd.Flags().BoolVar(&checkOCSP, "check-ocsp", false,
+		"Query the certificate's OCSP responder: revoked fails the check, unknown warns")
+	checkCmd.Flags().IntVar(&checkMaxRedirects, "max-redirects", 0,
+		"Fail when following more than this many redirects, e.g. a loop (0 = Go default of 10)")
+	checkCmd.Flags().Int64Var(&checkMaxHeaderBytes, "max-header-bytes", 0,
+		"Fail responses whose headers exceed this many bytes (0 = Go default, about 1MB)")
 	checkCmd.Flags().StringVarP(&checkOutput, "output", "o", "table",
-		"Output format (table/json)")
+		"Output format (table/json/influx)")
+	checkCmd.Flags().StringVar(&checkSaveConfig, "save-config", "",
+		"Write the checked URLs to this config file with each expected status set to the status received")
+	checkCmd.Flags().BoolVar(&checkTimingOnly, "timing-only", false,
+		"Print only the latency in milliseconds (errors go to stderr)")
+	checkCmd.Flags().BoolVar(&checkDumpRequest, "dump-request", false,
+		"Print the outgoing request line and headers to stderr")
+	checkCmd.Flags().BoolVar(&checkShowCert, "show-cert", false,
+		"Add the served certificate's subject, issuer, SANs and expiry under \"tls\" in JSON output (null for http URLs)")
 }
 
 // runCheck executes the check command
 func runCheck(cmd *cobra.Command, args []string) error {
-	targetURL := args[0]
-
 	// Validate URL format
-	if err := validateURL(targetURL); err != nil {
-		return fmt.Errorf("%w: %s", ErrConfig, err)
+	for _, targetURL := range args {
+		if err := validateURL(targetURL); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+	}
+
+	method := strings.ToUpper(checkMethod)
+	if !slices.Contains(checker.Methods, method) {
+		return fmt.Errorf("%w: invalid method '%s' (must be one of %s)", ErrConfig, checkMethod, strings.Join(checker.Methods, ", "))
+	}
+
+	// Request payload, sent as POST like curl unless a method was given
+	if checkData != "" && checkDataFile != "" {
+		return fmt.Errorf("%w: --data and --data-file are mutually exclusive", ErrConfig)
+	}
+	payload := checkData
+	if checkDataFile != "" {
+		data, err := os.ReadFile(checkDataFile)
+		if err != nil {
+			return fmt.Errorf("%w: failed to read data file: %s", ErrConfig, err)
+		}
+		payload = string(data)
+	}
+	if payload != "" && !cmd.Flags().Changed("method") {
+		method = "POST"
+	}
+
+	if checkTimingOnly && len(args) > 1 {
+		return fmt.Errorf("%w: --timing-only takes a single URL", ErrConfig)
 	}
 
 	// Parse headers
@@ -83,34 +224,158 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrConfig, err)
 	}
+	headerAssert, err := parseHeaderAsserts(checkAssertHeaders)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	// Validate CA files
+	if checkAddCACert != "" {
+		if _, err := checker.LoadCertPool(checkAddCACert); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+	}
+	if checkCACert != "" {
+		if _, err := checker.LoadCACertPool(checkCACert); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+	}
+	if checkClientCert != "" || checkClientKey != "" {
+		if _, err := checker.LoadClientCert(checkClientCert, checkClientKey); err != nil {
+			return fmt.Errorf("%w: --cert/--key: %s", ErrConfig, err)
+		}
+	}
+	if checkProxy != "" {
+		if _, err := checker.ParseProxyURL(checkProxy); err != nil {
+			return fmt.Errorf("%w: --proxy: %s", ErrConfig, err)
+		}
+	}
+	for _, entry := range checkResolve {
+		if _, _, err := checker.ParseResolve(entry); err != nil {
+			return fmt.Errorf("%w: --resolve: %s", ErrConfig, err)
+		}
+	}
+
+	if checkMaxRedirects < 0 {
+		return fmt.Errorf("%w: --max-redirects must not be negative", ErrConfig)
+	}
+	if checkMaxHeaderBytes < 0 {
+		return fmt.Errorf("%w: --max-header-bytes must not be negative", ErrConfig)
+	}
+
+	// Accepted status codes
+	if len(checkExpectedStatus) == 0 {
+		return fmt.Errorf("%w: --expected-status must not be empty", ErrConfig)
+	}
+	var codes []int
+	var ranges []checker.StatusRange
+	for _, status := range checkExpectedStatus {
+		r, err := checker.ParseStatusRange(status)
+		if err != nil {
+			return fmt.Errorf("%w: --expected-status: %s", ErrConfig, err)
+		}
+		if r.Min < 100 || r.Max > 599 {
+			return fmt.Errorf("%w: --expected-status must be between 100 and 599, got %s", ErrConfig, status)
+		}
+		if r.Min == r.Max {
+			codes = append(codes, r.Min)
+		} else {
+			ranges = append(ranges, r)
+		}
+	}
+	expectedStatus := 0
+	var expectedStatuses []int
+	switch {
+	case len(ranges) > 0:
+		expectedStatuses = codes
+	case len(codes) > 1:
+		expectedStatus = codes[0]
+		expectedStatuses = codes
+	default:
+		expectedStatus = codes[0]
+	}
 
 	// Create endpoint configuration
-	endpoint := checker.Endpoint{
-		Name:            targetURL,
-		URL:             targetURL,
-		Timeout:         checkTimeout,
-		Retries:         0,
-		ExpectedStatus:  checkExpectedStatus,
-		FollowRedirects: true,
-		Insecure:        checkInsecure,
-		Headers:         headers,
+	endpoints := make([]checker.Endpoint, len(args))
+	for i, targetURL := range args {
+		endpoints[i] = checker.Endpoint{
+			Name:             targetURL,
+			URL:              targetURL,
+			Method:           method,
+			Timeout:          checkTimeout,
+			Retries:          0,
+			ExpectedStatus:   expectedStatus,
+			ExpectedStatuses: expectedStatuses,
+			ExpectedRanges:   ranges,
+			FollowRedirects:  true,
+			Insecure:         checkInsecure,
+			Headers:          headers,
+			Body:             payload,
+			BodyContains:     checkBodyContains,
+			HeaderAssert:     headerAssert,
+			CACert:           checkCACert,
+			AddCACert:        checkAddCACert,
+			ClientCert:       checkClientCert,
+			ClientKey:        checkClientKey,
+			Proxy:            checkProxy,
+			Resolve:          checkResolve,
+			CompleteChain:    checkCompleteChain,
+			MaxHeaderBytes:   checkMaxHeaderBytes,
+			MaxRedirects:     checkMaxRedirects,
+		}
 	}
 
 	// Execute check
-	c := checker.New()
-	result := c.Check(endpoint)
+	opts := []checker.Option{
+		checker.WithDNSProbe(checkProbeDNS),
+		checker.WithDebug(verbose, unmask),
+		checker.WithCertInfo(checkShowCert),
+		checker.WithOCSP(checkOCSP),
+	}
+	if checkDumpRequest {
+		opts = append(opts, checker.WithRequestDump(os.Stderr, unmask))
+	}
+	c := checker.New(opts...)
+
+	// Several URLs are checked concurrently and reported as a batch
+	if len(endpoints) > 1 {
+		batch := maskBatch(c.CheckAll(endpoints))
+		formatter := newCheckFormatter()
+		if err := formatter.FormatBatch(batch); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		if err := saveCheckConfig(endpoints, batch.Results); err != nil {
+			return err
+		}
+		if batch.Summary.Unhealthy > 0 {
+			return ErrUnhealthy
+		}
+		return nil
+	}
+
+	result := maskResult(c.Check(endpoints[0]))
+
+	// Bare latency for scripting; nothing else goes to stdout
+	if checkTimingOnly {
+		if !result.Healthy {
+			fmt.Fprintln(os.Stderr, result.Error)
+			return ErrUnhealthy
+		}
+		fmt.Println(result.Latency.Milliseconds())
+		return saveCheckConfig(endpoints, []checker.Result{result})
+	}
 
 	// Format output
-	formatter := output.NewFormatter(
-		output.OutputFormat(checkOutput),
-		os.Stdout,
-		IsNoColor(),
-	)
+	formatter := newCheckFormatter()
 
 	if err := formatter.FormatSingle(result); err != nil {
 		return fmt.Errorf("failed to format output: %w", err)
 	}
 
+	if err := saveCheckConfig(endpoints, []checker.Result{result}); err != nil {
+		return err
+	}
+
 	// Return error if unhealthy (exit code 1)
 	if !result.Healthy {
 		return ErrUnhealthy
@@ -119,6 +384,35 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newCheckFormatter creates the stdout formatter, adding certificates to JSON with --show-cert
+func newCheckFormatter() output.Formatter {
+	if output.OutputFormat(checkOutput) == output.FormatJSON {
+		return output.NewJSONFormatter(os.Stdout, output.WithCert(checkShowCert))
+	}
+	return output.NewFormatter(output.OutputFormat(checkOutput), os.Stdout, IsNoColor(),
+		output.WithVerbose(verbose))
+}
+
+// saveCheckConfig writes the checked endpoints to --save-config, if set
+// Each expected status is pinned to the status the endpoint actually returned
+func saveCheckConfig(endpoints []checker.Endpoint, results []checker.Result) error {
+	if checkSaveConfig == "" {
+		return nil
+	}
+
+	cfg := config.FromResults(endpoints, results)
+	data, err := config.Marshal(cfg, config.FormatFromPath(checkSaveConfig))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(checkSaveConfig, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Saved %d endpoint(s) to %s\n", len(cfg.Endpoints), checkSaveConfig)
+	return nil
+}
+
 // validateURL validates URL format
 func validateURL(rawURL string) error {
 	// Check if URL has protocol
@@ -163,3 +457,43 @@ func parseHeaders(headerStrs []string) (map[string]string, error) {
 
 	return headers, nil
 }
+
+// mergeHeaders adds the --header values missing from an endpoint's headers, expanding
+// environment variables in them; header names match case-insensitively, as in HTTP
+func mergeHeaders(endpoint, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(endpoint)+len(extra))
+	for k, v := range endpoint {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		overridden := false
+		for name := range endpoint {
+			if strings.EqualFold(name, k) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged[k] = config.ExpandEnvVars(v)
+		}
+	}
+	return merged
+}
+
+// parseHeaderAsserts parses --assert-header flags, 'Name: value' or just 'Name' to require presence
+func parseHeaderAsserts(asserts []string) (map[string]string, error) {
+	if len(asserts) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(asserts))
+	for _, a := range asserts {
+		name, value, _ := strings.Cut(a, ":")
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header assertion '%s': expected 'Name: value' or 'Name'", a)
+		}
+		parsed[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+	return parsed, nil
+}
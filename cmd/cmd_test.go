@@ -0,0 +1,238 @@
+// Command module unit tests
+// Test command helpers that don't need a full CLI run
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"maps"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/config"
+)
+
+// captureOutput runs fn with stdout and stderr redirected to files and returns what each received
+func captureOutput(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	dir := t.TempDir()
+	outFile, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outFile.Close()
+	errFile, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer errFile.Close()
+
+	origOut, origErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outFile, errFile
+	defer func() { os.Stdout, os.Stderr = origOut, origErr }()
+	fn()
+
+	out, err := os.ReadFile(outFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOut, err := os.ReadFile(errFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), string(errOut)
+}
+
+// TestPostRunHook tests the hook receives the batch JSON and its output stays off stdout
+func TestPostRunHook(t *testing.T) {
+	batch := checker.BatchResult{
+		Results: []checker.Result{{Name: "api", URL: "https://api.example.com", Healthy: true}},
+		Summary: checker.Summary{Total: 1, Healthy: 1},
+	}
+
+	var hookErr error
+	stdout, stderr := captureOutput(t, func() {
+		hookErr = postRunHook("cat; echo hook-done", batch)
+	})
+	if hookErr != nil {
+		t.Fatalf("postRunHook() error = %v", hookErr)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q, want empty", stdout)
+	}
+	if !strings.Contains(stderr, `"name": "api"`) || !strings.Contains(stderr, "hook-done") {
+		t.Errorf("stderr = %q, want the batch JSON and the hook's output", stderr)
+	}
+
+	if err := postRunHook("exit 3", batch); err == nil || !strings.Contains(err.Error(), "post-run command failed") {
+		t.Errorf("postRunHook(exit 3) error = %v, want post-run command failed", err)
+	}
+}
+
+// TestMergeHeaders tests adding --header values to an endpoint's headers
+func TestMergeHeaders(t *testing.T) {
+	t.Setenv("HC_TEST_TRACE", "trace-1")
+
+	tests := []struct {
+		name     string
+		endpoint map[string]string
+		extra    map[string]string
+		want     map[string]string
+	}{
+		{"added", map[string]string{"Accept": "text/plain"}, map[string]string{"X-Env": "ci"},
+			map[string]string{"Accept": "text/plain", "X-Env": "ci"}},
+		{"endpoint wins", map[string]string{"Authorization": "Bearer a"}, map[string]string{"Authorization": "Bearer b"},
+			map[string]string{"Authorization": "Bearer a"}},
+		{"endpoint wins regardless of case", map[string]string{"authorization": "Bearer a"}, map[string]string{"AUTHORIZATION": "Bearer b"},
+			map[string]string{"authorization": "Bearer a"}},
+		{"env expanded", nil, map[string]string{"X-Trace-Id": "${HC_TEST_TRACE}"},
+			map[string]string{"X-Trace-Id": "trace-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := maps.Clone(tt.endpoint)
+			got := mergeHeaders(tt.endpoint, tt.extra)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("mergeHeaders() = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.endpoint, original) {
+				t.Errorf("endpoint headers modified: %v, want %v", tt.endpoint, original)
+			}
+		})
+	}
+}
+
+// writeTestCert writes a self-signed CA certificate and its key as PEM files in dir
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// TestCheckSaveConfig tests --save-config keeps the settings the check used
+func TestCheckSaveConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestCert(t, dir)
+
+	// File flags are given relative to the working directory and saved as absolute paths
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	absDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing listens on the proxy, so the check fails fast but the config is still saved
+	checkMethod, checkExpectedStatus, checkTimeout, checkOutput = "GET", []string{"204"}, time.Second, "json"
+	checkCACert, checkAddCACert = "ca.pem", "ca.pem"
+	checkClientCert, checkClientKey = "ca.pem", "ca-key.pem"
+	checkProxy = "http://127.0.0.1:1"
+	checkResolve = []string{"api.example.com:443:127.0.0.1"}
+	checkBodyContains = "ok"
+	checkAssertHeaders = []string{"X-Env: prod"}
+	checkMaxRedirects, checkMaxHeaderBytes, checkCompleteChain = 3, 4096, true
+	checkSaveConfig = filepath.Join(dir, "saved.yaml")
+	defer func() {
+		checkMethod, checkExpectedStatus, checkTimeout, checkOutput = "", nil, 0, ""
+		checkCACert, checkAddCACert, checkClientCert, checkClientKey, checkProxy = "", "", "", "", ""
+		checkResolve, checkBodyContains, checkAssertHeaders = nil, "", nil
+		checkMaxRedirects, checkMaxHeaderBytes, checkCompleteChain, checkSaveConfig = 0, 0, false, ""
+	}()
+
+	var runErr error
+	captureOutput(t, func() {
+		runErr = runCheck(checkCmd, []string{"https://api.example.com/health"})
+	})
+	if runErr != nil && !errors.Is(runErr, ErrUnhealthy) {
+		t.Fatalf("runCheck() error = %v", runErr)
+	}
+
+	cfg, err := config.Load(checkSaveConfig)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if result := config.ValidateConfigWithWarnings(cfg); len(result.Errors) > 0 {
+		t.Fatalf("saved config errors = %v", result.Errors)
+	}
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("len(endpoints) = %d, want 1", len(endpoints))
+	}
+
+	got := endpoints[0]
+	caFile, keyFile := filepath.Join(absDir, "ca.pem"), filepath.Join(absDir, "ca-key.pem")
+	checks := []struct {
+		field string
+		got   any
+		want  any
+	}{
+		{"ExpectedStatus", got.ExpectedStatus, 204},
+		{"CACert", got.CACert, caFile},
+		{"AddCACert", got.AddCACert, caFile},
+		{"ClientCert", got.ClientCert, caFile},
+		{"ClientKey", got.ClientKey, keyFile},
+		{"Proxy", got.Proxy, checkProxy},
+		{"BodyContains", got.BodyContains, "ok"},
+		{"HeaderAssert", got.HeaderAssert["X-Env"], "prod"},
+		{"MaxRedirects", got.MaxRedirects, 3},
+		{"MaxHeaderBytes", got.MaxHeaderBytes, int64(4096)},
+		{"CompleteChain", got.CompleteChain, true},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+		}
+	}
+	if !slices.Equal(got.Resolve, checkResolve) {
+		t.Errorf("Resolve = %v, want %v", got.Resolve, checkResolve)
+	}
+}
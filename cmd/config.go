@@ -4,6 +4,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/config"
 	"github.com/spf13/cobra"
@@ -12,7 +14,11 @@ import (
 // Config command flags
 var (
 	configInitFull     bool
+	configInitPrompt   bool
 	configValidatePath string
+	configStrict       bool
+	configConvertPath  string
+	configConvertTo    string
 )
 
 // configCmd is the config command group
@@ -23,7 +29,8 @@ var configCmd = &cobra.Command{
 
 Available subcommands:
   init      - Generate a sample configuration file
-  validate  - Validate an existing configuration file`,
+  validate  - Validate an existing configuration file
+  convert   - Convert a configuration file to another format`,
 }
 
 // configInitCmd is the config init subcommand
@@ -40,7 +47,10 @@ Examples:
   healthcheck config init > endpoints.yaml
 
   # Generate full configuration with all options
-  healthcheck config init --full > endpoints.yaml`,
+  healthcheck config init --full > endpoints.yaml
+
+  # Answer prompts for each endpoint instead (prompts go to stderr)
+  healthcheck config init --interactive > endpoints.yaml`,
 	RunE: runConfigInit,
 }
 
@@ -57,29 +67,83 @@ Checks for:
   - Valid timeout format
   - Valid status code range
 
+Warnings (such as unset environment variables) are printed but do not fail
+validation unless --strict is set.
+
 Examples:
   healthcheck config validate
   healthcheck config validate -c endpoints.yaml
-  healthcheck config validate -c /path/to/config.yaml`,
+  healthcheck config validate -c /path/to/config.yaml
+
+  # Fail on warnings too (for CI)
+  healthcheck config validate --strict`,
 	RunE: runConfigValidate,
 }
 
+// configConvertCmd is the config convert subcommand
+var configConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert a configuration file to another format",
+	Long: `Load a configuration file and re-emit it in another format (yaml, json or toml).
+
+The input format is inferred from the file extension. The output is written
+to stdout. Redirect to a file to save:
+  healthcheck config convert -c endpoints.yaml --to toml > endpoints.toml
+
+Examples:
+  healthcheck config convert -c endpoints.yaml --to json
+  healthcheck config convert -c endpoints.json --to yaml`,
+	RunE: runConfigConvert,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configConvertCmd)
 
 	// config init flags
 	configInitCmd.Flags().BoolVar(&configInitFull, "full", false,
 		"Generate full configuration with all available options")
+	configInitCmd.Flags().BoolVarP(&configInitPrompt, "interactive", "i", false,
+		"Prompt for each endpoint's name, URL, timeout and expected status and write the validated YAML")
 
 	// config validate flags
 	configValidateCmd.Flags().StringVarP(&configValidatePath, "config", "c", "endpoints.yaml",
 		"Path to configuration file to validate")
+	configValidateCmd.Flags().BoolVar(&configStrict, "strict", false,
+		"Fail validation if any warnings are present")
+	configValidateCmd.Flags().BoolVar(&configStrict, "fail-on-warning", false,
+		"Alias for --strict")
+	_ = configValidateCmd.Flags().MarkHidden("fail-on-warning")
+
+	// config convert flags
+	configConvertCmd.Flags().StringVarP(&configConvertPath, "config", "c", "endpoints.yaml",
+		"Path to configuration file to convert")
+	configConvertCmd.Flags().StringVar(&configConvertTo, "to", "",
+		"Target format (yaml/json/toml)")
+	_ = configConvertCmd.MarkFlagRequired("to")
 }
 
 // runConfigInit executes the config init command
 func runConfigInit(cmd *cobra.Command, args []string) error {
+	if configInitPrompt {
+		if configInitFull {
+			return fmt.Errorf("%w: --interactive cannot be combined with --full", ErrConfig)
+		}
+		// Prompts go to stderr so the YAML can be redirected to a file
+		cfg, err := config.PromptConfig(os.Stdin, os.Stderr)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		data, err := config.Marshal(cfg, "yaml")
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
 	sample := config.GenerateSampleConfig(configInitFull)
 	fmt.Print(sample)
 	return nil
@@ -94,16 +158,31 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate config
-	configErrors := config.ValidateConfig(cfg)
+	validation := config.ValidateConfigWithWarnings(cfg)
 
-	if len(configErrors) > 0 {
+	if len(validation.Errors) > 0 {
 		errMsg := "configuration validation failed:"
-		for _, e := range configErrors {
+		for _, e := range validation.Errors {
 			errMsg += "\n  - " + e
 		}
 		return fmt.Errorf("%w: %s", ErrConfig, errMsg)
 	}
 
+	if len(validation.Warnings) > 0 {
+		if configStrict {
+			errMsg := "configuration has warnings (--strict):"
+			for _, w := range validation.Warnings {
+				errMsg += "\n  - " + w
+			}
+			return fmt.Errorf("%w: %s", ErrConfig, errMsg)
+		}
+
+		fmt.Fprintf(os.Stderr, "Warnings:\n")
+		for _, w := range validation.Warnings {
+			fmt.Fprintf(os.Stderr, "  - %s\n", w)
+		}
+	}
+
 	// Try converting to endpoints to check parsing
 	endpoints, err := cfg.ToCheckerEndpoints()
 	if err != nil {
@@ -123,3 +202,23 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runConfigConvert executes the config convert command
+func runConfigConvert(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configConvertPath)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	data, err := config.Marshal(cfg, strings.ToLower(configConvertTo))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	output := string(data)
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	fmt.Print(output)
+	return nil
+}
@@ -0,0 +1,31 @@
+// Post-run hook
+// Pipes the batch result as JSON into a user-supplied shell command
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/output"
+)
+
+// postRunHook runs command through the shell with the batch JSON on stdin
+// The command's output goes to stderr, keeping stdout for the report (e.g. -o json or --nagios)
+func postRunHook(command string, batch checker.BatchResult) error {
+	var buf bytes.Buffer
+	if err := output.NewJSONFormatter(&buf).FormatBatch(batch); err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = &buf
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post-run command failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Run profiling
+// Writes pprof profiles and reports time and allocations per check
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// profiler collects the run's --profile, --cpuprofile and --memprofile output
+// A nil profiler is disabled; all methods are safe to call on it
+type profiler struct {
+	report  bool
+	cpuFile *os.File
+	memPath string
+
+	start  time.Time
+	before runtime.MemStats
+	checks int
+}
+
+// startProfiler starts CPU profiling and takes the baseline memory statistics
+// It returns nil when no profiling flag is set
+func startProfiler(report bool, cpuPath, memPath string) (*profiler, error) {
+	if !report && cpuPath == "" && memPath == "" {
+		return nil, nil
+	}
+
+	p := &profiler{report: report, memPath: memPath}
+	if cpuPath != "" {
+		f, err := os.Create(cpuPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		p.cpuFile = f
+	}
+
+	runtime.ReadMemStats(&p.before)
+	p.start = time.Now()
+	return p, nil
+}
+
+// add counts checks performed while profiling
+func (p *profiler) add(checks int) {
+	if p != nil {
+		p.checks += checks
+	}
+}
+
+// stop finishes the CPU profile, writes the heap profile and prints the per-check report to stderr
+func (p *profiler) stop() error {
+	if p == nil {
+		return nil
+	}
+	elapsed := time.Since(p.start)
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	if p.cpuFile != nil {
+		pprof.StopCPUProfile()
+		if err := p.cpuFile.Close(); err != nil {
+			return fmt.Errorf("failed to write CPU profile: %w", err)
+		}
+	}
+
+	if p.memPath != "" {
+		f, err := os.Create(p.memPath)
+		if err != nil {
+			return fmt.Errorf("failed to create memory profile: %w", err)
+		}
+		runtime.GC() // Up-to-date allocation statistics
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write memory profile: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write memory profile: %w", err)
+		}
+	}
+
+	if p.report && p.checks > 0 {
+		n := uint64(p.checks)
+		fmt.Fprintf(os.Stderr, "\nProfile:\n")
+		fmt.Fprintf(os.Stderr, "  Checks:           %d in %s\n", p.checks, elapsed.Round(time.Microsecond))
+		fmt.Fprintf(os.Stderr, "  Wall per check:   %s\n", (elapsed / time.Duration(p.checks)).Round(time.Microsecond))
+		fmt.Fprintf(os.Stderr, "  Allocs per check: %d\n", (after.Mallocs-p.before.Mallocs)/n)
+		fmt.Fprintf(os.Stderr, "  Bytes per check:  %d\n", (after.TotalAlloc-p.before.TotalAlloc)/n)
+	}
+	return nil
+}
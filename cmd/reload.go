@@ -0,0 +1,60 @@
+// Config reload
+// Watches the config file and signals when it changes on disk
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long the config file must stay unchanged before a reload
+// Editors and config management often write a file in several steps
+const reloadDebounce = 250 * time.Millisecond
+
+// watchConfigFile sends on the returned channel after path changes, until ctx is canceled
+// The directory is watched rather than the file, so atomic replacement by rename is noticed
+func watchConfigFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config: %w", err)
+	}
+
+	changed := make(chan struct{}, 1)
+	go func() {
+		defer watcher.Close()
+		debounce := time.NewTimer(0)
+		<-debounce.C
+		for {
+			select {
+			case <-ctx.Done():
+				debounce.Stop()
+				return
+			case event := <-watcher.Events:
+				if filepath.Clean(event.Name) == abs && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					debounce.Reset(reloadDebounce)
+				}
+			case err := <-watcher.Errors:
+				fmt.Fprintf(os.Stderr, "Warning: config watch: %v\n", err)
+			case <-debounce.C:
+				select {
+				case changed <- struct{}{}:
+				default: // A reload is already pending
+				}
+			}
+		}
+	}()
+	return changed, nil
+}
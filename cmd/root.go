@@ -4,9 +4,12 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -16,11 +19,27 @@ var (
 	ErrConfig = errors.New("configuration error")
 	// ErrUnhealthy indicates unhealthy endpoint(s) (exit code 1)
 	ErrUnhealthy = errors.New("unhealthy endpoint")
+	// ErrLatencySLA indicates healthy endpoint(s) exceeded the run latency limit (exit code 1)
+	ErrLatencySLA = errors.New("latency SLA breached")
+	// ErrNothingChecked indicates every configured endpoint was skipped (exit code 3)
+	ErrNothingChecked = errors.New("no endpoints checked")
 )
 
+// exitCodeError ends the command with a specific exit code, for output contracts such as --nagios
+// Its outcome has already been printed, so the error itself is not shown
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 // Global variables
 var (
 	noColor bool
+	unmask  bool
+	verbose bool
 )
 
 // rootCmd is the CLI root command
@@ -39,10 +58,17 @@ Example usage:
 // Execute executes the root command and handles exit codes
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		// Cobra already prints the error, so we just need to set exit code
 		if errors.Is(err, ErrConfig) {
 			os.Exit(2)
 		}
+		if errors.Is(err, ErrNothingChecked) {
+			os.Exit(3)
+		}
 		os.Exit(1)
 	}
 }
@@ -53,6 +79,10 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&unmask, "unmask", false,
+		"Show secret values: query parameters such as api_key in output, headers in --dump-request and --verbose, and secrets in --include-config")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
+		"Show request and response headers, failed assertions and connection phase timings below each result")
 
 	// Support NO_COLOR environment variable (https://no-color.org/)
 	if os.Getenv("NO_COLOR") != "" {
@@ -69,3 +99,36 @@ func init() {
 func IsNoColor() bool {
 	return noColor
 }
+
+// maskBatch masks secret query parameter values in batch results unless --unmask is set
+func maskBatch(batch checker.BatchResult) checker.BatchResult {
+	if unmask {
+		return batch
+	}
+	return output.MaskBatch(batch)
+}
+
+// maskResult masks secret query parameter values in a result unless --unmask is set
+func maskResult(r checker.Result) checker.Result {
+	if unmask {
+		return r
+	}
+	return output.MaskResult(r)
+}
+
+// maskEndpoints masks secrets in endpoint configuration unless --unmask is set
+func maskEndpoints(endpoints []checker.Endpoint) []checker.Endpoint {
+	if unmask {
+		return endpoints
+	}
+	return output.MaskEndpoints(endpoints)
+}
+
+// columnsFromEnv returns the terminal width from the COLUMNS environment variable, or 0 if unset
+func columnsFromEnv() int {
+	cols, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || cols <= 0 {
+		return 0
+	}
+	return cols
+}
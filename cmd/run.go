@@ -3,24 +3,106 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
 	"github.com/r1ckyIn/healthcheck-cli/internal/config"
+	"github.com/r1ckyIn/healthcheck-cli/internal/history"
 	"github.com/r1ckyIn/healthcheck-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 // Run command flags
 var (
-	runConfigPath  string
-	runTimeout     time.Duration
-	runConcurrency int
-	runOutput      string
-	runQuiet       bool
-	runInsecure    bool
+	runConfigPath      string
+	runRegistry        string
+	runTimeout         time.Duration
+	runConcurrency     int
+	runOutputs         []string
+	runHeaders         []string
+	runAssertHeaders   []string
+	runQuiet           bool
+	runInsecure        bool
+	runInsecureDefault bool
+	runHistory         string
+	runAdaptive        bool
+	runTags            []string
+	runNameWidth       int
+	runURLWidth        int
+	runJitter          float64
+	runSeed            int64
+	runDebugPool       bool
+	runSection         bool
+	runTree            bool
+	runCollapseHealthy bool
+	runNoValidate      bool
+	runFlapThreshold   int
+	runRecoverThresh   int
+	runAnomalyFactor   float64
+	runEMAAlpha        float64
+	runProfile         bool
+	runBodyCheckEvery  int
+	runCPUProfile      string
+	runMemProfile      string
+	runCountByKind     bool
+	runRetryNetOnly    bool
+	runAddCACert       string
+	runCACert          string
+	runClientCert      string
+	runClientKey       string
+	runProxy           string
+	runResolve         []string
+	runCheckOCSP       bool
+	runSort            string
+	runLimit           int
+	runCompleteChain   bool
+	runStatusFileDir   string
+	runWatch           time.Duration
+	runDelayFirst      bool
+	runSets            []string
+	runChangedSince    string
+	runTimeoutMult     float64
+	runStream          bool
+	runTagsAll         []string
+	runPostRun         string
+	runPostRunExit     bool
+	runMaxHeaderBytes  int64
+	runMaxRedirects    int
+	runCacheCheck      bool
+	runCacheHeader     string
+	runMaxLatency      time.Duration
+	runRandomOrder     bool
+	runAssertAll       bool
+	runRotateUA        bool
+	runUAFile          string
+	runProbeDNS        bool
+	runDetectChanges   string
+	runTimestamps      bool
+	runAutoConc        bool
+	runRatePerHost     float64
+	runIncludeConfig   bool
+	runNagios          bool
+	runNagiosWarning   int
+	runNagiosCritical  int
+)
+
+// stdinPath as --config reads a URL list from stdin
+const stdinPath = "-"
+
+// Adaptive timeout tuning
+const (
+	adaptiveTimeoutFactor = 3.0
+	adaptiveTimeoutMin    = 100 * time.Millisecond
 )
 
 // runCmd is the run subcommand
@@ -36,17 +118,144 @@ Examples:
   # Basic usage
   healthcheck run -c endpoints.yaml
 
+  # Check services from a registry export instead of a config file
+  healthcheck run --registry services.json
+
+  # Ad-hoc URLs on stdin, each optionally followed by a timeout and expected status
+  printf '%s\n' 'https://api.example.com/health 10s' 'https://example.com/new 201' | healthcheck run -c -
+
+  # Tweak a single endpoint without editing the config
+  healthcheck run -c endpoints.yaml --set 'endpoints[0].timeout=30s'
+
+  # Run a deliberately unusual config without the validation checks
+  healthcheck run -c endpoints.yaml --no-validate
+
+  # Check every service config, or only those changed in this branch
+  healthcheck run -c 'services/*.yaml'
+  healthcheck run -c 'services/*.yaml' --changed-since origin/main
+
   # Override timeout for all endpoints
   healthcheck run -c endpoints.yaml --timeout 10s
 
+  # Double every timeout on slow CI runners
+  healthcheck run -c endpoints.yaml --timeout-multiplier 2
+
   # Increase concurrency
   healthcheck run -c endpoints.yaml --concurrency 20
 
+  # Let concurrency adapt to backend health, up to 50 checks at once
+  healthcheck run -c endpoints.yaml --auto-concurrency --concurrency 50
+
+  # Never hit any single backend with more than 5 checks per second
+  healthcheck run -c endpoints.yaml --rate-per-host 5
+
   # JSON output for CI/CD
   healthcheck run -c endpoints.yaml -o json
 
+  # Table on the console and a JSON artifact from the same run
+  healthcheck run -c endpoints.yaml -o table -o json:results.json
+
+  # Send a tracing header with every check, keeping headers set in config
+  healthcheck run -c endpoints.yaml -H 'X-Trace-Id: ${TRACE_ID}'
+
+  # InfluxDB line protocol, e.g. for Telegraf's exec input
+  healthcheck run -c endpoints.yaml -o influx
+
+  # Self-describing JSON artifact including the endpoint settings used
+  healthcheck run -c endpoints.yaml -o json:results.json --include-config
+
+  # Print each row as soon as its check finishes (completion order)
+  healthcheck run -c endpoints.yaml --stream
+
+  # Group failures at the top of the table
+  healthcheck run -c endpoints.yaml --section
+
+  # Group endpoints by host, folding hosts that are fully healthy
+  healthcheck run -c endpoints.yaml --tree --collapse-healthy
+
+  # Show only the five slowest endpoints, summarizing all of them
+  healthcheck run -c endpoints.yaml --sort latency --limit 5
+
+  # Show what kind of failures dominate (e.g. "5 dns, 3 timeout")
+  healthcheck run -c endpoints.yaml --count-by-kind
+
+  # Verify the CDN caches each endpoint after a cache flush
+  healthcheck run -c endpoints.yaml --cache-check --cache-header CF-Cache-Status
+
+  # Trust a private CA while public certificates still verify
+  healthcheck run -c endpoints.yaml --add-cacert internal-ca.pem
+
+  # Trust only an internal CA bundle instead of the system trust store
+  healthcheck run -c endpoints.yaml --cacert internal-ca.pem
+
+  # Authenticate with a client certificate (mutual TLS)
+  healthcheck run -c endpoints.yaml --cert client.crt --key client.key
+
+  # Fail endpoints still serving a revoked certificate
+  healthcheck run -c endpoints.yaml --check-ocsp
+
+  # Route every check through a proxy
+  healthcheck run -c endpoints.yaml --proxy http://proxy.corp.example.com:3128
+
+  # Check one node behind the load balancer, keeping Host and SNI
+  healthcheck run -c endpoints.yaml --resolve api.example.com:443:10.0.3.12
+
+  # Reach internal services through a SOCKS5 tunnel to a bastion host
+  healthcheck run -c endpoints.yaml --proxy socks5://localhost:1080
+
+  # Run as a Nagios plugin: CRITICAL once 3 endpoints are down, WARNING from 1
+  healthcheck run -c endpoints.yaml --nagios --nagios-warning 1 --nagios-critical 3
+
+  # Only check endpoints tagged "critical"
+  healthcheck run -c endpoints.yaml --tag critical
+
+  # Only check endpoints tagged both "prod" and "critical"
+  healthcheck run -c endpoints.yaml --tag-all prod --tag-all critical
+
+  # One status file per endpoint for CI matrix jobs
+  healthcheck run -c endpoints.yaml --status-file-dir status/
+
+  # Re-check every 30 seconds until interrupted
+  healthcheck run -c endpoints.yaml --watch 30s
+
+  # Push the batch JSON to a custom API after each run
+  healthcheck run -c endpoints.yaml --post-run 'curl -s -d @- https://metrics.example.com/ingest'
+
+  # Fail the deploy gate if any endpoint is slower than 1s, even when healthy
+  healthcheck run -c endpoints.yaml --max-latency-global 1s
+
+  # Contract-test mode: evaluate every assertion and list each one that failed
+  healthcheck run -c endpoints.yaml --assert-all
+
+  # Vary the User-Agent per request for endpoints that rate-limit by User-Agent
+  healthcheck run -c endpoints.yaml --watch 30s --rotate-user-agent
+
+  # Watch a status page for content changes between runs
+  healthcheck run -c endpoints.yaml --watch 5m --detect-changes state.json
+
+  # Poll cheaply with HEAD, validating response bodies with a full GET every 10th cycle
+  healthcheck run -c endpoints.yaml --watch 10s --body-check-every 10
+
+  # Warn when an endpoint gets twice as slow as its recent p95
+  healthcheck run -c endpoints.yaml --watch 30s --anomaly-factor 2
+
+  # Show a smoothed typical latency next to each cycle's value
+  healthcheck run -c endpoints.yaml --watch 30s --ema-alpha 0.3
+
+  # Ignore single-cycle blips: report down or recovered after 3 cycles in a row
+  healthcheck run -c endpoints.yaml --watch 30s --flap-threshold 3
+
+  # Report down after 2 failed cycles, but recovered only after 5 healthy ones
+  healthcheck run -c endpoints.yaml --watch 30s --flap-threshold 2 --recover-threshold 5
+
   # Quiet mode (exit code only)
-  healthcheck run -c endpoints.yaml -q`,
+  healthcheck run -c endpoints.yaml -q
+
+  # Size a large run: time and allocations per check, plus pprof profiles
+  healthcheck run -c endpoints.yaml -q --profile --cpuprofile cpu.out --memprofile mem.out
+
+  # Record results and derive timeouts from each endpoint's historical p99
+  healthcheck run -c endpoints.yaml --history history.jsonl --adaptive-timeout`,
 	RunE: runRun,
 }
 
@@ -55,40 +264,198 @@ func init() {
 
 	// Define flags
 	runCmd.Flags().StringVarP(&runConfigPath, "config", "c", "endpoints.yaml",
-		"Path to configuration file")
+		"Path to configuration file, a glob such as 'services/*.yaml' to load several, or '-' for URLs on stdin")
+	runCmd.Flags().StringVar(&runChangedSince, "changed-since", "",
+		"Only check config files changed since this git revision (all files if git fails, none and exit 0 if none changed)")
+	runCmd.Flags().StringVar(&runRegistry, "registry", "",
+		"Load endpoints from a service registry export (JSON list of {name, healthUrl}) instead of --config")
+	runCmd.Flags().BoolVar(&runNoValidate, "no-validate", false,
+		"Skip config validation (conversion errors such as a missing url or bad timeout still fail)")
+	runCmd.Flags().StringArrayVar(&runSets, "set", nil,
+		"Override a config value after loading, e.g. 'endpoints[0].timeout=30s' (can be used multiple times)")
 	runCmd.Flags().DurationVarP(&runTimeout, "timeout", "t", 0,
 		"Override timeout for all endpoints (e.g., 5s, 10s)")
+	runCmd.Flags().Float64Var(&runTimeoutMult, "timeout-multiplier", 1,
+		"Scale every configured timeout and retry delay, e.g. 2.0 for slow CI runners")
 	runCmd.Flags().IntVarP(&runConcurrency, "concurrency", "n", 10,
 		"Maximum concurrent checks")
-	runCmd.Flags().StringVarP(&runOutput, "output", "o", "table",
-		"Output format (table/json)")
+	runCmd.Flags().BoolVar(&runAutoConc, "auto-concurrency", false,
+		"Start with low concurrency, ramp up while checks are healthy and back off on timeouts (--concurrency is the ceiling)")
+	runCmd.Flags().Float64Var(&runRatePerHost, "rate-per-host", 0,
+		"Send at most this many requests per second to each host, evenly spaced, retries included (e.g. 2 or 0.5; 0 = unlimited)")
+	runCmd.Flags().StringArrayVarP(&runOutputs, "output", "o", []string{"table"},
+		"Output as format[:path] (table/json/influx; path '-' or omitted is stdout, can be used multiple times)")
+	runCmd.Flags().BoolVar(&runIncludeConfig, "include-config", false,
+		"Embed the resolved endpoint configuration under a \"config\" key in JSON output (secrets masked unless --unmask)")
+	runCmd.Flags().BoolVar(&runNagios, "nagios", false,
+		"Act as a Nagios/Icinga plugin: print one status line with performance data and exit 0 OK, 1 WARNING, 2 CRITICAL or 3 UNKNOWN")
+	runCmd.Flags().IntVar(&runNagiosWarning, "nagios-warning", 1,
+		"With --nagios, unhealthy endpoint count that is WARNING (degraded endpoints are always WARNING)")
+	runCmd.Flags().IntVar(&runNagiosCritical, "nagios-critical", 1,
+		"With --nagios, unhealthy endpoint count that is CRITICAL")
 	runCmd.Flags().BoolVarP(&runQuiet, "quiet", "q", false,
-		"Quiet mode (no output, exit code only)")
+		"Quiet mode (no stdout output, exit code only; file outputs are still written)")
 	runCmd.Flags().BoolVarP(&runInsecure, "insecure", "k", false,
 		"Skip SSL certificate verification for all endpoints")
+	runCmd.Flags().StringArrayVar(&runAssertHeaders, "assert-header", nil,
+		"Require a response header on every endpoint, as 'Name: value' or 'Name' for presence (can be used multiple times; overrides header_assert for the same header)")
+	runCmd.Flags().StringArrayVarP(&runHeaders, "header", "H", nil,
+		"Add a header to every endpoint, e.g. 'X-Trace-Id: ${TRACE_ID}' (can be used multiple times; endpoint headers win)")
+	runCmd.Flags().BoolVar(&runProbeDNS, "probe-dns", false,
+		"Resolve each host before the HTTP request, failing early on DNS errors and reporting resolved IPs in JSON")
+	runCmd.Flags().StringVar(&runAddCACert, "add-cacert", "",
+		"PEM file of CA certificates to trust in addition to the system trust store (overrides add_cacert in config)")
+	runCmd.Flags().StringVar(&runCACert, "cacert", "",
+		"PEM file of CA certificates to trust instead of the system trust store (overrides ca_cert in config)")
+	runCmd.Flags().StringVar(&runClientCert, "cert", "",
+		"PEM client certificate for mutual TLS, used with --key (overrides client certificates in config)")
+	runCmd.Flags().StringVar(&runClientKey, "key", "",
+		"PEM private key of the --cert client certificate")
+	runCmd.Flags().StringVar(&runProxy, "proxy", "",
+		"Proxy URL for all endpoints, e.g. http://proxy:3128 or socks5://bastion:1080 (overrides proxy in config, endpoints with no_proxy stay direct; default HTTP_PROXY/HTTPS_PROXY)")
+	runCmd.Flags().StringArrayVar(&runResolve, "resolve", nil,
+		"Connect to addr for host:port while keeping the host for Host and SNI, as host:port:addr (can be used multiple times, takes precedence over resolve in config)")
+	runCmd.Flags().BoolVar(&runCompleteChain, "require-complete-chain", false,
+		"Fail when a server omits intermediate certificates, even if they are in the trust store")
+	runCmd.Flags().BoolVar(&runCheckOCSP, "check-ocsp", false,
+		"Query each certificate's OCSP responder: revoked fails the check, unknown warns (adds a request per HTTPS endpoint)")
+	runCmd.Flags().IntVar(&runMaxRedirects, "max-redirects", 0,
+		"Fail endpoints that follow more than this many redirects, e.g. loops (overrides max_redirects in config; 0 = Go default of 10)")
+	runCmd.Flags().Int64Var(&runMaxHeaderBytes, "max-header-bytes", 0,
+		"Fail responses whose headers exceed this many bytes (overrides max_header_bytes in config; 0 = Go default, about 1MB)")
+	runCmd.Flags().BoolVar(&runInsecureDefault, "insecure-default", false,
+		"Skip SSL certificate verification by default (endpoints with 'insecure: false' stay verified)")
+	runCmd.Flags().IntVar(&runNameWidth, "name-width", 0,
+		"Maximum NAME column width in table output (default: fit terminal)")
+	runCmd.Flags().IntVar(&runURLWidth, "url-width", 0,
+		"Maximum URL column width in table output (default: fit terminal)")
+	runCmd.Flags().DurationVar(&runMaxLatency, "max-latency-global", 0,
+		"Exit non-zero if any endpoint's latency exceeds this, even when all are healthy (e.g., 1s; WARNING with --nagios)")
+	runCmd.Flags().BoolVar(&runAssertAll, "assert-all", false,
+		"Evaluate every assertion instead of stopping at the first failure (implies --verbose)")
+	runCmd.Flags().DurationVarP(&runWatch, "watch", "w", 0,
+		"Repeat the checks at this interval until interrupted (e.g., 30s, 1m)")
+	runCmd.Flags().IntVar(&runFlapThreshold, "flap-threshold", 1,
+		"In watch mode, report an endpoint down or recovered only after this many consecutive cycles agree")
+	runCmd.Flags().IntVar(&runRecoverThresh, "recover-threshold", 0,
+		"In watch mode, report a down endpoint recovered only after this many consecutive healthy cycles (default: --flap-threshold)")
+	runCmd.Flags().Float64Var(&runAnomalyFactor, "anomaly-factor", 0,
+		"In watch mode, warn when a latency exceeds this multiple of the endpoint's rolling p95 (e.g. 2.0, 0 = off)")
+	runCmd.Flags().Float64Var(&runEMAAlpha, "ema-alpha", 0,
+		"In watch mode, add an EMA column with each endpoint's moving average latency, weighting new values by this (0-1, e.g. 0.3, 0 = off)")
+	runCmd.Flags().IntVar(&runBodyCheckEvery, "body-check-every", 1,
+		"In watch mode, check endpoints with body assertions using HEAD and only run the full GET every Nth cycle")
+	runCmd.Flags().BoolVar(&runDelayFirst, "delay-first", false,
+		"In watch mode, wait one interval before the first check instead of starting immediately")
+	runCmd.Flags().BoolVar(&runStream, "stream", false,
+		"Print table rows as each check completes (rows appear in completion order, not config order)")
+	runCmd.Flags().BoolVar(&runCacheCheck, "cache-check", false,
+		"Request each endpoint twice; the second response must report a cache hit or the server must answer at least 2x faster")
+	runCmd.Flags().StringVar(&runCacheHeader, "cache-header", "X-Cache",
+		"Response header reporting cache status for --cache-check")
+	runCmd.Flags().BoolVar(&runTimestamps, "timestamps", false,
+		"Add checked_at and completed_at to each result in JSON output")
+	runCmd.Flags().BoolVar(&runSection, "section", false,
+		"Group table output into FAILURES and OK sections, failures first")
+	runCmd.Flags().BoolVar(&runTree, "tree", false,
+		"Group table output under each URL host with a per-host health rollup")
+	runCmd.Flags().BoolVar(&runCollapseHealthy, "collapse-healthy", false,
+		"With --tree, show hosts whose endpoints are all healthy as a single line")
+	runCmd.Flags().StringVar(&runSort, "sort", "",
+		"Order displayed results worst first (latency/failure, default config order)")
+	runCmd.Flags().IntVar(&runLimit, "limit", 0,
+		"Display only the first N results; the summary still covers all (0 = all)")
+	runCmd.Flags().BoolVar(&runCountByKind, "count-by-kind", false,
+		"Add a breakdown of failures by error kind (dns, timeout, tls, ...) to the summary")
+	runCmd.Flags().Float64Var(&runJitter, "retry-jitter", 0,
+		"Randomize each retry delay by up to +/- this percentage (0-100)")
+	runCmd.Flags().BoolVar(&runRetryNetOnly, "retry-network-only", false,
+		"Only retry connection-level failures (DNS, timeout, refused); wrong responses fail immediately")
+	runCmd.Flags().BoolVar(&runRotateUA, "rotate-user-agent", false,
+		"Send a random common browser User-Agent with each request (explicit User-Agent headers still apply)")
+	runCmd.Flags().StringVar(&runUAFile, "user-agent-file", "",
+		"Rotate through the User-Agents in this file, one per line (implies --rotate-user-agent)")
+	runCmd.Flags().BoolVar(&runRandomOrder, "randomize-order", false,
+		"Start checks in random order (results are still shown in config order)")
+	runCmd.Flags().Int64Var(&runSeed, "seed", 0,
+		"Seed for randomized behavior such as retry jitter, --randomize-order and --rotate-user-agent (0 = random)")
+	runCmd.Flags().BoolVar(&runProfile, "profile", false,
+		"Print wall time, allocations and bytes allocated per check to stderr after the run")
+	runCmd.Flags().StringVar(&runCPUProfile, "cpuprofile", "",
+		"Write a pprof CPU profile of the checks to this file")
+	runCmd.Flags().StringVar(&runMemProfile, "memprofile", "",
+		"Write a pprof heap profile to this file after the checks")
+	runCmd.Flags().BoolVar(&runDebugPool, "debug-pool", false,
+		"Print connection reuse statistics to stderr after the results")
+	runCmd.Flags().StringArrayVar(&runTags, "tag", nil,
+		"Only check endpoints with this tag (can be used multiple times, matches any)")
+	runCmd.Flags().StringArrayVar(&runTagsAll, "tag-all", nil,
+		"Only check endpoints with all of these tags (can be used multiple times, combines with --tag)")
+	runCmd.Flags().StringVar(&runStatusFileDir, "status-file-dir", "",
+		"Write a <name>.status file (0 healthy, 1 unhealthy) per endpoint into this directory")
+	runCmd.Flags().StringVar(&runPostRun, "post-run", "",
+		"Shell command to run after each batch, with the full JSON results on its stdin (its output goes to stderr)")
+	runCmd.Flags().BoolVar(&runPostRunExit, "post-run-exit", false,
+		"Exit with an error when the --post-run command fails (default: warn only)")
+	runCmd.Flags().StringVar(&runHistory, "history", "",
+		"Append results to this history file (JSON Lines)")
+	runCmd.Flags().StringVar(&runDetectChanges, "detect-changes", "",
+		"Store response body hashes in this state file and warn on endpoints whose body changed since the last run")
+	runCmd.Flags().BoolVar(&runAdaptive, "adaptive-timeout", false,
+		"Set each endpoint's timeout to 3x its historical p99 latency (requires --history)")
 }
 
 // runRun executes the run command
-func runRun(cmd *cobra.Command, args []string) error {
-	// Load config file
-	cfg, err := config.Load(runConfigPath)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrConfig, err)
+func runRun(cmd *cobra.Command, args []string) (err error) {
+	// The status line is the only output; any other error is reported as UNKNOWN
+	if runNagios {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		defer func() {
+			err = nagiosError(err)
+		}()
 	}
 
-	// Validate config
-	if configErrors := config.ValidateConfig(cfg); len(configErrors) > 0 {
-		errMsg := "configuration validation failed:"
-		for _, e := range configErrors {
-			errMsg += "\n  - " + e
+	// Parse output destinations
+	specs := make([]output.OutputSpec, 0, len(runOutputs))
+	for _, o := range runOutputs {
+		spec, err := output.ParseOutputSpec(o)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
 		}
-		return fmt.Errorf("%w: %s", ErrConfig, errMsg)
+		specs = append(specs, spec)
 	}
 
-	// Convert to checker.Endpoint
-	endpoints, err := cfg.ToCheckerEndpoints()
+	// Load, validate and convert config files or registry
+	endpoints, warnings, err := loadRunEndpoints()
+	if errors.Is(err, errNoChangedConfigs) {
+		// Nothing to check is success here, e.g. for a CI job on a commit that didn't touch configs
+		if !runQuiet {
+			fmt.Fprintf(os.Stderr, "No config files changed since %s, nothing to check\n", runChangedSince)
+		}
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrConfig, err)
+		return err
+	}
+	if !runQuiet {
+		printWarnings(warnings)
+	}
+
+	// Select endpoints to check
+	configured := len(endpoints)
+	endpoints = checker.FilterByTags(endpoints, runTags)
+	endpoints = checker.FilterByAllTags(endpoints, runTagsAll)
+	if len(endpoints) == 0 {
+		return fmt.Errorf("%w: all %d configured endpoints were skipped by filters", ErrNothingChecked, configured)
+	}
+
+	// Scale configured timeouts for the environment; an explicit --timeout still wins
+	if runTimeoutMult <= 0 {
+		return fmt.Errorf("%w: --timeout-multiplier must be positive", ErrConfig)
+	}
+	for i := range endpoints {
+		endpoints[i].Timeout = time.Duration(float64(endpoints[i].Timeout) * runTimeoutMult)
 	}
 
 	// Apply command line override flags
@@ -98,27 +465,323 @@ func runRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Disabling verification from the command line is always reported, even with -q or --no-validate
 	if runInsecure {
+		printWarnings([]string{"--insecure: SSL certificate verification is disabled for all endpoints"})
 		for i := range endpoints {
 			endpoints[i].Insecure = true
 		}
+	} else if runInsecureDefault {
+		printWarnings([]string{"--insecure-default: SSL certificate verification is disabled for endpoints without 'insecure: false'"})
+	}
+
+	if len(runHeaders) > 0 {
+		headers, err := parseHeaders(runHeaders)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].Headers = mergeHeaders(endpoints[i].Headers, headers)
+		}
+	}
+
+	if len(runAssertHeaders) > 0 {
+		asserts, err := parseHeaderAsserts(runAssertHeaders)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			merged := make(map[string]string, len(endpoints[i].HeaderAssert)+len(asserts))
+			maps.Copy(merged, endpoints[i].HeaderAssert)
+			maps.Copy(merged, asserts)
+			endpoints[i].HeaderAssert = merged
+		}
+	}
+
+	if runAddCACert != "" {
+		if _, err := checker.LoadCertPool(runAddCACert); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].AddCACert = runAddCACert
+		}
+	}
+
+	if runCACert != "" {
+		if _, err := checker.LoadCACertPool(runCACert); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].CACert = runCACert
+		}
+	}
+
+	if runClientCert != "" || runClientKey != "" {
+		if _, err := checker.LoadClientCert(runClientCert, runClientKey); err != nil {
+			return fmt.Errorf("%w: --cert/--key: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].ClientCert = runClientCert
+			endpoints[i].ClientKey = runClientKey
+			endpoints[i].ClientCertP12 = ""
+			endpoints[i].ClientCertP12Pass = ""
+		}
+	}
+
+	if runProxy != "" {
+		if _, err := checker.ParseProxyURL(runProxy); err != nil {
+			return fmt.Errorf("%w: --proxy: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].Proxy = runProxy
+		}
+	}
+
+	if len(runResolve) > 0 {
+		for _, entry := range runResolve {
+			if _, _, err := checker.ParseResolve(entry); err != nil {
+				return fmt.Errorf("%w: --resolve: %s", ErrConfig, err)
+			}
+		}
+		// The first entry for a host and port wins, so flags go first
+		for i := range endpoints {
+			endpoints[i].Resolve = append(slices.Clone(runResolve), endpoints[i].Resolve...)
+		}
+	}
+
+	if runCompleteChain {
+		for i := range endpoints {
+			endpoints[i].CompleteChain = true
+		}
+	}
+
+	if runMaxRedirects < 0 {
+		return fmt.Errorf("%w: --max-redirects must not be negative", ErrConfig)
+	}
+	if runMaxRedirects > 0 {
+		for i := range endpoints {
+			endpoints[i].MaxRedirects = runMaxRedirects
+		}
+	}
+
+	if runMaxHeaderBytes < 0 {
+		return fmt.Errorf("%w: --max-header-bytes must not be negative", ErrConfig)
+	}
+	if runMaxHeaderBytes > 0 {
+		for i := range endpoints {
+			endpoints[i].MaxHeaderBytes = runMaxHeaderBytes
+		}
+	}
+
+	// Derive timeouts from recorded latency history
+	if runAdaptive {
+		if runHistory == "" {
+			return fmt.Errorf("%w: --adaptive-timeout requires --history", ErrConfig)
+		}
+		records, err := history.Load(runHistory)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		for i := range endpoints {
+			endpoints[i].Timeout = history.AdaptiveTimeout(records, endpoints[i].Name,
+				adaptiveTimeoutFactor, adaptiveTimeoutMin, endpoints[i].Timeout)
+		}
+	}
+
+	if runTree && runSection {
+		return fmt.Errorf("%w: --tree cannot be combined with --section", ErrConfig)
+	}
+	if runCollapseHealthy && !runTree {
+		return fmt.Errorf("%w: --collapse-healthy requires --tree", ErrConfig)
+	}
+
+	switch checker.SortKey(runSort) {
+	case "", checker.SortByLatency, checker.SortByFailure:
+	default:
+		return fmt.Errorf("%w: invalid --sort '%s': must be latency or failure", ErrConfig, runSort)
+	}
+	if runLimit < 0 {
+		return fmt.Errorf("%w: --limit must not be negative", ErrConfig)
+	}
+
+	if runStream {
+		if runSection || runTree {
+			return fmt.Errorf("%w: --stream cannot be combined with --section or --tree", ErrConfig)
+		}
+		if runSort != "" || runLimit > 0 {
+			return fmt.Errorf("%w: --stream cannot be combined with --sort or --limit", ErrConfig)
+		}
+		if runFlapThreshold > 1 || runRecoverThresh > 1 {
+			return fmt.Errorf("%w: --stream cannot be combined with --flap-threshold or --recover-threshold", ErrConfig)
+		}
+		if runAnomalyFactor > 0 {
+			return fmt.Errorf("%w: --stream cannot be combined with --anomaly-factor", ErrConfig)
+		}
+		if runEMAAlpha > 0 {
+			return fmt.Errorf("%w: --stream cannot be combined with --ema-alpha", ErrConfig)
+		}
+		if !slices.ContainsFunc(specs, isStdoutTable) {
+			return fmt.Errorf("%w: --stream requires table output on stdout", ErrConfig)
+		}
+	}
+
+	if runNagios {
+		if runWatch > 0 || runStream {
+			return fmt.Errorf("%w: --nagios cannot be combined with --watch or --stream", ErrConfig)
+		}
+		if runNagiosWarning < 1 || runNagiosCritical < runNagiosWarning {
+			return fmt.Errorf("%w: --nagios-warning must be at least 1 and at most --nagios-critical", ErrConfig)
+		}
+	}
+
+	if runIncludeConfig && !slices.ContainsFunc(specs, isJSON) {
+		return fmt.Errorf("%w: --include-config requires JSON output (-o json)", ErrConfig)
+	}
+
+	if runCacheCheck && runCacheHeader == "" {
+		return fmt.Errorf("%w: --cache-header must not be empty", ErrConfig)
+	}
+
+	if runPostRunExit && runPostRun == "" {
+		return fmt.Errorf("%w: --post-run-exit requires --post-run", ErrConfig)
+	}
+
+	if runMaxLatency < 0 {
+		return fmt.Errorf("%w: --max-latency-global must not be negative", ErrConfig)
+	}
+
+	if runFlapThreshold < 1 {
+		return fmt.Errorf("%w: --flap-threshold must be at least 1", ErrConfig)
+	}
+	if runRecoverThresh < 0 {
+		return fmt.Errorf("%w: --recover-threshold must not be negative", ErrConfig)
+	}
+	if (runFlapThreshold > 1 || runRecoverThresh > 1) && runWatch <= 0 {
+		return fmt.Errorf("%w: --flap-threshold and --recover-threshold require --watch", ErrConfig)
+	}
+	if runAnomalyFactor != 0 && runAnomalyFactor <= 1 {
+		return fmt.Errorf("%w: --anomaly-factor must be greater than 1", ErrConfig)
+	}
+	if runAnomalyFactor > 0 && runWatch <= 0 {
+		return fmt.Errorf("%w: --anomaly-factor requires --watch", ErrConfig)
+	}
+	if runEMAAlpha < 0 || runEMAAlpha > 1 {
+		return fmt.Errorf("%w: --ema-alpha must be between 0 and 1", ErrConfig)
+	}
+	if runEMAAlpha > 0 && runWatch <= 0 {
+		return fmt.Errorf("%w: --ema-alpha requires --watch", ErrConfig)
+	}
+	if runBodyCheckEvery < 1 {
+		return fmt.Errorf("%w: --body-check-every must be at least 1", ErrConfig)
+	}
+	if runBodyCheckEvery > 1 && runWatch <= 0 {
+		return fmt.Errorf("%w: --body-check-every requires --watch", ErrConfig)
+	}
+
+	if runDelayFirst && runWatch <= 0 {
+		return fmt.Errorf("%w: --delay-first requires --watch", ErrConfig)
+	}
+
+	if runJitter < 0 || runJitter > 100 {
+		return fmt.Errorf("%w: --retry-jitter must be between 0 and 100", ErrConfig)
+	}
+
+	if runRatePerHost < 0 {
+		return fmt.Errorf("%w: --rate-per-host must not be negative", ErrConfig)
 	}
 
 	// Create checker and execute
-	c := checker.New(checker.WithConcurrency(runConcurrency))
-	result := c.CheckAll(endpoints)
+	opts := []checker.Option{
+		checker.WithConcurrency(runConcurrency),
+		checker.WithRetryJitter(runJitter),
+		checker.WithRetryNetworkOnly(runRetryNetOnly),
+		checker.WithRetryDelayScale(runTimeoutMult),
+		checker.WithRandomOrder(runRandomOrder),
+		checker.WithAssertAll(runAssertAll),
+		checker.WithDNSProbe(runProbeDNS),
+		checker.WithBodyHash(runDetectChanges != ""),
+		checker.WithTimestamps(runTimestamps),
+		checker.WithAutoConcurrency(runAutoConc),
+		checker.WithRatePerHost(runRatePerHost),
+		checker.WithDebug(verbose, unmask),
+		checker.WithOCSP(runCheckOCSP),
+	}
+	if runSeed != 0 {
+		opts = append(opts, checker.WithSeed(runSeed))
+	}
+	if runCacheCheck {
+		opts = append(opts, checker.WithCacheCheck(runCacheHeader))
+	}
+	if runRotateUA || runUAFile != "" {
+		agents := checker.DefaultUserAgents
+		if runUAFile != "" {
+			if agents, err = checker.LoadUserAgents(runUAFile); err != nil {
+				return fmt.Errorf("%w: %s", ErrConfig, err)
+			}
+		}
+		opts = append(opts, checker.WithUserAgents(agents))
+	}
+	c := checker.New(opts...)
 
-	// Output results
-	if !runQuiet {
-		formatter := output.NewFormatter(
-			output.OutputFormat(runOutput),
-			os.Stdout,
-			IsNoColor(),
-		)
+	// Profile only the checks, not config loading
+	prof, err := startProfiler(runProfile, runCPUProfile, runMemProfile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if stopErr := prof.stop(); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}()
 
-		if err := formatter.FormatBatch(result); err != nil {
-			return fmt.Errorf("failed to format output: %w", err)
+	// Watch mode repeats the batch until interrupted
+	if runWatch > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		var flaps *history.FlapFilter
+		if runFlapThreshold > 1 || runRecoverThresh > 1 {
+			flaps = history.NewFlapFilter(runFlapThreshold, runRecoverThresh)
 		}
+		var anomalies *history.AnomalyDetector
+		if runAnomalyFactor > 0 {
+			anomalies = history.NewAnomalyDetector(runAnomalyFactor)
+		}
+		var emas *history.EMATracker
+		if runEMAAlpha > 0 {
+			emas = history.NewEMATracker(runEMAAlpha)
+		}
+		// Body assertions run on the first cycle and every Nth one after, HEAD otherwise
+		headEndpoints := lightEndpoints(endpoints)
+		cycle := 0
+		return watch(ctx, runWatch, runDelayFirst, func(ctx context.Context) error {
+			cycleEndpoints := endpoints
+			if runBodyCheckEvery > 1 && cycle%runBodyCheckEvery != 0 {
+				cycleEndpoints = headEndpoints
+			}
+			cycle++
+			result, err := runCycle(ctx, c, cycleEndpoints, specs, flaps, anomalies, emas)
+			prof.add(len(result.Results))
+			return err
+		})
+	}
+
+	result, err := runCycle(context.Background(), c, endpoints, specs, nil, nil, nil)
+	prof.add(len(result.Results))
+	if err != nil {
+		return err
+	}
+
+	if runNagios {
+		// Latency breaches are reported as WARNING, as the gate below is skipped
+		thresholds := output.NagiosThresholds{Warning: runNagiosWarning, Critical: runNagiosCritical, MaxLatency: runMaxLatency}
+		state, err := output.WriteNagios(os.Stdout, result, thresholds)
+		if err != nil {
+			return err
+		}
+		if state != output.NagiosOK {
+			return &exitCodeError{code: int(state)}
+		}
+		return nil
 	}
 
 	// Return error if any unhealthy endpoints (exit code 1)
@@ -126,5 +789,371 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return ErrUnhealthy
 	}
 
+	// Run-level latency gate on otherwise healthy results
+	if runMaxLatency > 0 {
+		if slow := slowEndpoints(result.Results, runMaxLatency); len(slow) > 0 {
+			return fmt.Errorf("%w: %d endpoint(s) exceeded %s: %s", ErrLatencySLA, len(slow), runMaxLatency, strings.Join(slow, ", "))
+		}
+	}
+
 	return nil
 }
+
+// nagiosError reports a failed --nagios run as UNKNOWN on stdout
+// Results already reported by the status line pass through unchanged
+func nagiosError(err error) error {
+	var exitErr *exitCodeError
+	if err == nil || errors.As(err, &exitErr) {
+		return err
+	}
+	fmt.Printf("%s - %v\n", output.NagiosUnknown, err)
+	return &exitCodeError{code: int(output.NagiosUnknown)}
+}
+
+// lightEndpoints replaces GET endpoints that assert on the body with their HEAD variant
+func lightEndpoints(endpoints []checker.Endpoint) []checker.Endpoint {
+	light := make([]checker.Endpoint, len(endpoints))
+	for i, ep := range endpoints {
+		light[i], _ = ep.HeadCheck()
+	}
+	return light
+}
+
+// slowEndpoints lists results slower than limit as "name (latency)"
+func slowEndpoints(results []checker.Result, limit time.Duration) []string {
+	var slow []string
+	for _, r := range results {
+		if r.Latency > limit {
+			slow = append(slow, fmt.Sprintf("%s (%s)", r.Name, r.Latency.Round(time.Millisecond)))
+		}
+	}
+	return slow
+}
+
+// runCycle checks all endpoints once and writes every configured output
+// A non-nil flaps filter holds back state changes that have not yet persisted across cycles,
+// a non-nil anomalies detector warns about latencies far above the recent p95,
+// and a non-nil emas tracker fills in each result's moving average latency
+func runCycle(ctx context.Context, c *checker.Checker, endpoints []checker.Endpoint, specs []output.OutputSpec, flaps *history.FlapFilter, anomalies *history.AnomalyDetector, emas *history.EMATracker) (checker.BatchResult, error) {
+	// Stream table rows to stdout as checks complete
+	var stream *output.TableFormatter
+	if runStream && !runQuiet {
+		stream = output.NewTableFormatter(os.Stdout, IsNoColor(),
+			output.WithTerminalWidth(terminalWidth()),
+			output.WithColumnWidths(runNameWidth, runURLWidth),
+			output.WithVerbose(verbose || runAssertAll))
+		if err := stream.BeginStream(endpoints); err != nil {
+			return checker.BatchResult{}, fmt.Errorf("failed to format output: %w", err)
+		}
+	}
+
+	var streamErr error
+	result := c.CheckAllStream(ctx, endpoints, func(r checker.Result) {
+		if stream != nil && streamErr == nil {
+			streamErr = stream.StreamRow(maskResult(r))
+		}
+	})
+	if streamErr != nil {
+		return result, fmt.Errorf("failed to format output: %w", streamErr)
+	}
+	if ctx.Err() != nil {
+		// Interrupted mid-cycle; don't record or print canceled checks
+		return result, nil
+	}
+	result = maskBatch(result)
+	if runCountByKind {
+		result.Summary.FailuresByKind = checker.CountByKind(result.Results)
+	}
+
+	// Connection pool statistics for transport tuning
+	if runDebugPool {
+		stats := c.ConnStats()
+		fmt.Fprintf(os.Stderr, "\nConnection pool:\n")
+		fmt.Fprintf(os.Stderr, "  New connections:    %d\n", stats.New)
+		fmt.Fprintf(os.Stderr, "  Reused connections: %d\n", stats.Reused)
+	}
+
+	// Flag endpoints whose body changed since the last run
+	if runDetectChanges != "" {
+		state, err := history.LoadBodyState(runDetectChanges)
+		if err != nil {
+			return result, err
+		}
+		state.DetectChanges(result.Results)
+		if err := state.Save(runDetectChanges); err != nil {
+			return result, err
+		}
+	}
+
+	// Record results for future baselines
+	if runHistory != "" {
+		if err := history.Append(runHistory, result); err != nil {
+			return result, err
+		}
+	}
+
+	// Compare observed latencies with each endpoint's rolling baseline
+	if anomalies != nil {
+		anomalies.Apply(&result)
+	}
+	if emas != nil {
+		emas.Apply(&result)
+	}
+
+	// Report state changes only once they persist; history keeps the observed results
+	if flaps != nil {
+		flaps.Apply(&result)
+		if runCountByKind {
+			result.Summary.FailuresByKind = checker.CountByKind(result.Results)
+		}
+	}
+
+	// Per-endpoint status files for CI matrix jobs
+	if runStatusFileDir != "" {
+		if err := output.WriteStatusFiles(runStatusFileDir, result.Results); err != nil {
+			return result, err
+		}
+	}
+
+	// Output results to each destination
+	display := displayBatch(result)
+	for _, spec := range specs {
+		if stream != nil && isStdoutTable(spec) {
+			if err := stream.EndStream(result); err != nil {
+				return result, fmt.Errorf("failed to format output: %w", err)
+			}
+			continue
+		}
+		if err := writeBatchOutput(spec, display, endpoints); err != nil {
+			return result, err
+		}
+	}
+
+	// Aggregate processing by a user command
+	if runPostRun != "" {
+		if err := postRunHook(runPostRun, result); err != nil {
+			if runPostRunExit {
+				return result, err
+			}
+			printWarnings([]string{err.Error()})
+		}
+	}
+
+	return result, nil
+}
+
+// displayBatch applies --sort and --limit to the results that are printed
+// The summary is left as is, so it still covers every checked endpoint
+func displayBatch(result checker.BatchResult) checker.BatchResult {
+	if runSort == "" && runLimit == 0 {
+		return result
+	}
+	result.Results = slices.Clone(result.Results)
+	if runSort != "" {
+		// The key was validated with the other flags
+		_ = checker.SortResults(result.Results, checker.SortKey(runSort))
+	}
+	if runLimit > 0 && len(result.Results) > runLimit {
+		result.Results = result.Results[:runLimit]
+	}
+	return result
+}
+
+// errNoChangedConfigs is returned by loadRunEndpoints when --changed-since finds no changed config files
+var errNoChangedConfigs = errors.New("no config files changed")
+
+// loadRunEndpoints loads the run command's endpoints from the registry or config files
+// A config glob may match several files; each keeps its own defaults
+func loadRunEndpoints() ([]checker.Endpoint, []string, error) {
+	if runRegistry != "" {
+		cfg, err := config.LoadRegistry(runRegistry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		return prepareRunConfig(cfg)
+	}
+
+	// Ad-hoc URL list, one "url [timeout] [status]" per line
+	if runConfigPath == stdinPath {
+		if runChangedSince != "" {
+			return nil, nil, fmt.Errorf("%w: --changed-since requires config files, not stdin", ErrConfig)
+		}
+		cfg, err := config.ParseURLList(os.Stdin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		return prepareRunConfig(cfg)
+	}
+
+	paths, err := configPaths(runConfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	// Narrow to files changed since a git revision
+	var warnings []string
+	if runChangedSince != "" {
+		changed, err := changedFiles(runChangedSince, paths)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("--changed-since: %s; checking all config files", err))
+		} else if len(changed) == 0 {
+			return nil, nil, errNoChangedConfigs
+		} else {
+			paths = changed
+		}
+	}
+
+	if len(paths) > 1 && len(runSets) > 0 {
+		return nil, nil, fmt.Errorf("%w: --set requires a single config file, %d matched", ErrConfig, len(paths))
+	}
+
+	var endpoints []checker.Endpoint
+	for _, path := range paths {
+		cfg, err := config.Load(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		eps, w, err := prepareRunConfig(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		endpoints = append(endpoints, eps...)
+		warnings = append(warnings, w...)
+	}
+
+	return endpoints, warnings, nil
+}
+
+// prepareRunConfig applies run flag overrides to a config, then validates and converts it
+func prepareRunConfig(cfg *config.Config) ([]checker.Endpoint, []string, error) {
+	// Inline overrides are applied before validation so bad values are caught
+	if err := cfg.ApplyOverrides(runSets); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	// Override defaults before conversion so per-endpoint settings still win
+	if runInsecureDefault {
+		cfg.Defaults.Insecure = true
+	}
+
+	// Unusual configs can skip the opinionated checks; conversion errors are still fatal
+	if runNoValidate {
+		endpoints, err := cfg.ToCheckerEndpoints()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+		}
+		return endpoints, nil, nil
+	}
+
+	return toEndpoints(cfg)
+}
+
+// loadEndpoints loads and validates a config file and converts it to checker endpoints
+// Validation warnings are printed to stderr
+func loadEndpoints(path string) ([]checker.Endpoint, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	endpoints, warnings, err := toEndpoints(cfg)
+	if err != nil {
+		return nil, err
+	}
+	printWarnings(warnings)
+
+	return endpoints, nil
+}
+
+// toEndpoints validates a config and converts it to checker endpoints, returning validation warnings
+func toEndpoints(cfg *config.Config) ([]checker.Endpoint, []string, error) {
+	// Validate config
+	validation := config.ValidateConfigWithWarnings(cfg)
+	if len(validation.Errors) > 0 {
+		errMsg := "configuration validation failed:"
+		for _, e := range validation.Errors {
+			errMsg += "\n  - " + e
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfig, errMsg)
+	}
+
+	// Convert to checker.Endpoint
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+
+	return endpoints, validation.Warnings, nil
+}
+
+// printWarnings prints validation warnings to stderr
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+}
+
+// isStdoutTable reports whether an output spec is a table written to stdout
+func isStdoutTable(spec output.OutputSpec) bool {
+	return spec.Format == output.FormatTable && spec.Path == output.StdoutPath
+}
+
+// isJSON reports whether an output spec is JSON
+func isJSON(spec output.OutputSpec) bool {
+	return spec.Format == output.FormatJSON
+}
+
+// newBatchFormatter creates the formatter for one output destination
+// JSON output embeds the checked endpoints with --include-config
+func newBatchFormatter(spec output.OutputSpec, w io.Writer, noColor bool, endpoints []checker.Endpoint, tableOpts ...output.TableOption) output.Formatter {
+	if runIncludeConfig && isJSON(spec) {
+		return output.NewJSONFormatter(w, output.WithConfig(maskEndpoints(endpoints)))
+	}
+	return output.NewFormatter(spec.Format, w, noColor, tableOpts...)
+}
+
+// writeBatchOutput formats batch results to a single output destination
+// Quiet mode suppresses stdout output only; file outputs are always written
+func writeBatchOutput(spec output.OutputSpec, result checker.BatchResult, endpoints []checker.Endpoint) error {
+	if spec.Path == output.StdoutPath {
+		if runQuiet || runNagios {
+			return nil
+		}
+		formatter := newBatchFormatter(
+			spec,
+			os.Stdout,
+			IsNoColor(),
+			endpoints,
+			output.WithTerminalWidth(terminalWidth()),
+			output.WithColumnWidths(runNameWidth, runURLWidth),
+			output.WithSections(runSection),
+			output.WithTree(runTree),
+			output.WithCollapseHealthy(runCollapseHealthy),
+			output.WithVerbose(verbose || runAssertAll),
+			output.WithEMA(runEMAAlpha > 0),
+		)
+		if err := formatter.FormatBatch(result); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(spec.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	formatter := newBatchFormatter(spec, file, true, endpoints,
+		output.WithColumnWidths(runNameWidth, runURLWidth),
+		output.WithSections(runSection),
+		output.WithTree(runTree),
+		output.WithCollapseHealthy(runCollapseHealthy),
+		output.WithVerbose(verbose || runAssertAll),
+		output.WithEMA(runEMAAlpha > 0))
+	if err := formatter.FormatBatch(result); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,182 @@
+// Serve command
+// Runs endpoint checks continuously on per-endpoint cron schedules
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/output"
+	"github.com/r1ckyIn/healthcheck-cli/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+// Serve command flags
+var (
+	serveConfigPath      string
+	serveDefaultSchedule string
+	serveConcurrency     int
+	serveOutput          string
+	serveWatchConfig     bool
+)
+
+// serveCmd is the serve subcommand
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run health checks continuously on a schedule",
+	Long: `Run health checks as a long-running process, checking each endpoint on its
+own cron schedule until interrupted.
+
+Each endpoint may set 'schedule' to a standard 5-field cron expression
+(minute hour day-of-month month day-of-week). Endpoints without one use
+--schedule. Endpoints that fall due at the same minute are checked together
+and printed as one batch.
+
+Examples:
+  # Check every endpoint each minute unless it sets its own schedule
+  healthcheck serve -c endpoints.yaml
+
+  # Default to every five minutes, JSON output for log shipping
+  healthcheck serve -c endpoints.yaml --schedule "*/5 * * * *" -o json
+
+  # Pick up added and removed endpoints without restarting
+  healthcheck serve -c endpoints.yaml --watch-config`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	// Define flags
+	serveCmd.Flags().StringVarP(&serveConfigPath, "config", "c", "endpoints.yaml",
+		"Path to configuration file")
+	serveCmd.Flags().StringVar(&serveDefaultSchedule, "schedule", "* * * * *",
+		"Cron expression for endpoints without their own schedule")
+	serveCmd.Flags().IntVarP(&serveConcurrency, "concurrency", "n", 10,
+		"Maximum concurrent checks")
+	serveCmd.Flags().StringVarP(&serveOutput, "output", "o", "table",
+		"Output format (table/json/influx)")
+	serveCmd.Flags().BoolVar(&serveWatchConfig, "watch-config", false,
+		"Reload the config file when it changes; an invalid config is rejected and the previous one kept")
+}
+
+// scheduledEndpoint pairs an endpoint with its schedule and next run time
+type scheduledEndpoint struct {
+	endpoint checker.Endpoint
+	schedule *schedule.Schedule
+	next     time.Time
+}
+
+// runServe executes the serve command
+func runServe(cmd *cobra.Command, args []string) error {
+	switch output.OutputFormat(serveOutput) {
+	case output.FormatTable, output.FormatJSON, output.FormatInflux:
+	default:
+		return fmt.Errorf("%w: invalid output format '%s', must be 'table', 'json' or 'influx'", ErrConfig, serveOutput)
+	}
+
+	defaultSchedule, err := schedule.Parse(serveDefaultSchedule)
+	if err != nil {
+		return fmt.Errorf("%w: --schedule: %s", ErrConfig, err)
+	}
+
+	endpoints, err := loadEndpoints(serveConfigPath)
+	if err != nil {
+		return err
+	}
+	entries, err := scheduleEndpoints(endpoints, defaultSchedule, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A nil channel never fires when the config is not watched
+	var reload <-chan struct{}
+	if serveWatchConfig {
+		if reload, err = watchConfigFile(ctx, serveConfigPath); err != nil {
+			return err
+		}
+	}
+
+	c := checker.New(checker.WithConcurrency(serveConcurrency))
+	formatter := output.NewFormatter(output.OutputFormat(serveOutput), os.Stdout, IsNoColor(),
+		output.WithTerminalWidth(terminalWidth()))
+
+	for {
+		// Sleep until the earliest endpoint is due
+		due := entries[0].next
+		for _, e := range entries[1:] {
+			if e.next.Before(due) {
+				due = e.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(due))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-reload:
+			timer.Stop()
+			if reloaded, err := reloadEndpoints(serveConfigPath, defaultSchedule); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: config reload rejected, keeping the previous config: %v\n", err)
+			} else {
+				entries = reloaded
+				fmt.Fprintf(os.Stderr, "Reloaded config: %d endpoints\n", len(entries))
+			}
+			continue
+		case <-timer.C:
+		}
+
+		// Collect every endpoint due at this time and schedule its next run
+		var batch []checker.Endpoint
+		for _, e := range entries {
+			if !e.next.After(due) {
+				batch = append(batch, e.endpoint)
+				e.next = e.schedule.Next(due)
+			}
+		}
+
+		result := maskBatch(c.CheckAll(batch))
+		if err := formatter.FormatBatch(result); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+	}
+}
+
+// scheduleEndpoints resolves each endpoint's schedule and first run after now
+func scheduleEndpoints(endpoints []checker.Endpoint, defaultSchedule *schedule.Schedule, now time.Time) ([]*scheduledEndpoint, error) {
+	entries := make([]*scheduledEndpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		sched := defaultSchedule
+		if ep.Schedule != "" {
+			// Already validated at config load
+			var err error
+			if sched, err = schedule.Parse(ep.Schedule); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrConfig, err)
+			}
+		}
+		next := sched.Next(now)
+		if next.IsZero() {
+			return nil, fmt.Errorf("%w: endpoint '%s': schedule '%s' never runs", ErrConfig, ep.Name, sched)
+		}
+		entries = append(entries, &scheduledEndpoint{endpoint: ep, schedule: sched, next: next})
+	}
+	return entries, nil
+}
+
+// reloadEndpoints loads, validates and schedules the config again after it changed on disk
+func reloadEndpoints(path string, defaultSchedule *schedule.Schedule) ([]*scheduledEndpoint, error) {
+	endpoints, err := loadEndpoints(path)
+	if err != nil {
+		return nil, err
+	}
+	return scheduleEndpoints(endpoints, defaultSchedule, time.Now())
+}
@@ -0,0 +1,9 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || zos)
+
+// Terminal width detection fallback for other platforms
+package cmd
+
+// terminalWidth returns the stdout terminal width in columns, or 0 if unknown
+func terminalWidth() int {
+	return columnsFromEnv()
+}
@@ -0,0 +1,114 @@
+// Top command
+// Shows the worst endpoints from a batch check
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+	"github.com/r1ckyIn/healthcheck-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Top command flags
+var (
+	topConfigPath  string
+	topBy          string
+	topLimit       int
+	topTimeout     time.Duration
+	topConcurrency int
+	topOutput      string
+)
+
+// topCmd is the top subcommand
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show the slowest or unhealthiest endpoints",
+	Long: `Run health checks on all endpoints in a configuration file and show only
+the worst N endpoints by the chosen metric.
+
+Metrics:
+  latency  - Slowest endpoints first
+  failure  - Unhealthy endpoints first (connection failures before bad
+             responses), then slowest
+
+The summary line still covers every checked endpoint.
+
+Examples:
+  # Ten slowest endpoints
+  healthcheck top -c endpoints.yaml
+
+  # Five worst failures
+  healthcheck top -c endpoints.yaml --by failure --limit 5`,
+	RunE: runTop,
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+
+	// Define flags
+	topCmd.Flags().StringVarP(&topConfigPath, "config", "c", "endpoints.yaml",
+		"Path to configuration file")
+	topCmd.Flags().StringVar(&topBy, "by", string(checker.SortByLatency),
+		"Metric to rank by (latency/failure)")
+	topCmd.Flags().IntVar(&topLimit, "limit", 10,
+		"Number of endpoints to show")
+	topCmd.Flags().DurationVarP(&topTimeout, "timeout", "t", 0,
+		"Override timeout for all endpoints (e.g., 5s, 10s)")
+	topCmd.Flags().IntVarP(&topConcurrency, "concurrency", "n", 10,
+		"Maximum concurrent checks")
+	topCmd.Flags().StringVarP(&topOutput, "output", "o", "table",
+		"Output format (table/json/influx)")
+}
+
+// runTop executes the top command
+func runTop(cmd *cobra.Command, args []string) error {
+	if topLimit <= 0 {
+		return fmt.Errorf("%w: --limit must be positive", ErrConfig)
+	}
+	switch checker.SortKey(topBy) {
+	case checker.SortByLatency, checker.SortByFailure:
+	default:
+		return fmt.Errorf("%w: invalid --by '%s': must be latency or failure", ErrConfig, topBy)
+	}
+
+	endpoints, err := loadEndpoints(topConfigPath)
+	if err != nil {
+		return err
+	}
+
+	if topTimeout > 0 {
+		for i := range endpoints {
+			endpoints[i].Timeout = topTimeout
+		}
+	}
+
+	c := checker.New(checker.WithConcurrency(topConcurrency))
+	result := maskBatch(c.CheckAll(endpoints))
+
+	// Rank and keep the worst N
+	if err := checker.SortResults(result.Results, checker.SortKey(topBy)); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfig, err)
+	}
+	if len(result.Results) > topLimit {
+		result.Results = result.Results[:topLimit]
+	}
+
+	formatter := output.NewFormatter(
+		output.OutputFormat(topOutput),
+		os.Stdout,
+		IsNoColor(),
+		output.WithTerminalWidth(terminalWidth()),
+	)
+	if err := formatter.FormatBatch(result); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	if result.Summary.Unhealthy > 0 {
+		return ErrUnhealthy
+	}
+
+	return nil
+}
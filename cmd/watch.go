@@ -0,0 +1,32 @@
+// Watch loop
+// Repeats a check cycle at a fixed interval
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// watch runs cycle every interval until ctx is canceled
+// The first cycle runs immediately unless delayFirst is set
+func watch(ctx context.Context, interval time.Duration, delayFirst bool, cycle func(context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if !delayFirst {
+		if err := cycle(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := cycle(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
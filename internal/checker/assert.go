@@ -0,0 +1,199 @@
+// Response assertions
+// Evaluates the configured assertions against a response
+package checker
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AssertionType groups assertions by what they inspect
+type AssertionType string
+
+// Assertion types
+const (
+	AssertStatus  AssertionType = "status"  // Status line: status code and protocol version
+	AssertHeader  AssertionType = "header"  // Response headers such as Content-Type and Location
+	AssertBody    AssertionType = "body"    // Response body content and size
+	AssertLatency AssertionType = "latency" // Response latency
+	AssertTLS     AssertionType = "tls"     // TLS handshake properties
+)
+
+// AssertionResult is the outcome of a single response assertion
+type AssertionResult struct {
+	Type   AssertionType // What the assertion inspected
+	Passed bool          // Whether the assertion held
+	Detail string        // What was asserted when passed, the failure reason otherwise
+}
+
+// assertion is a configured check run against the response
+type assertion struct {
+	typ   AssertionType
+	kind  ErrorKind // Failure classification
+	desc  string    // Detail reported when the assertion passes
+	check func() error
+}
+
+// WithAssertAll evaluates every configured assertion instead of stopping at the first failure
+func WithAssertAll(enabled bool) Option {
+	return func(c *Checker) {
+		c.assertAll = enabled
+	}
+}
+
+// assertions lists the assertions configured for the endpoint in evaluation order
+// Content-Type is checked before body assertions and latency last
+func assertions(ep Endpoint, resp *http.Response, body func() (string, error), latency time.Duration) []assertion {
+	var list []assertion
+	add := func(typ AssertionType, kind ErrorKind, desc string, check func() error) {
+		list = append(list, assertion{typ, kind, desc, check})
+	}
+
+	if len(ep.ForbiddenStatus) > 0 {
+		add(AssertStatus, KindStatus, fmt.Sprintf("status not in %v", ep.ForbiddenStatus), func() error {
+			if slices.Contains(ep.ForbiddenStatus, resp.StatusCode) {
+				return fmt.Errorf("forbidden status code: got %d", resp.StatusCode)
+			}
+			return nil
+		})
+	}
+
+	// Expected status depends on the method of the final request
+	if expected := ep.expectedStatusesFor(resp.Request.Method); len(expected) > 0 {
+		add(AssertStatus, KindStatus, "status "+formatStatuses(expected), func() error {
+			if !slices.ContainsFunc(expected, func(r StatusRange) bool { return r.Contains(resp.StatusCode) }) {
+				return fmt.Errorf("unexpected status code: got %d, expected %s", resp.StatusCode, formatStatuses(expected))
+			}
+			return nil
+		})
+	}
+
+	if len(ep.TLSALPN) > 0 && resp.TLS != nil {
+		add(AssertTLS, KindTLS, "ALPN protocol negotiated", func() error {
+			if resp.TLS.NegotiatedProtocol == "" {
+				return fmt.Errorf("no ALPN protocol negotiated (offered %s)", strings.Join(ep.TLSALPN, ", "))
+			}
+			return nil
+		})
+	}
+
+	if ep.CompleteChain && resp.TLS != nil {
+		add(AssertTLS, KindTLS, "complete certificate chain", func() error {
+			roots, err := ep.rootCAs()
+			if err != nil {
+				return err
+			}
+			return checkCompleteChain(resp.TLS.PeerCertificates, roots)
+		})
+	}
+
+	if ep.ExpectedProto != "" {
+		add(AssertStatus, KindProto, "protocol "+ep.ExpectedProto, func() error {
+			if resp.Proto != ep.ExpectedProto {
+				return fmt.Errorf("unexpected protocol: got %s, expected %s", resp.Proto, ep.ExpectedProto)
+			}
+			return nil
+		})
+	}
+
+	// Redirect target only applies to redirect responses
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 && (ep.ExpectedLocation != "" || ep.LocationPattern != nil) {
+		add(AssertHeader, KindLocation, "redirect location", func() error {
+			return checkLocation(resp.Header.Get("Location"), ep)
+		})
+	}
+
+	if ep.Accept != "" {
+		add(AssertHeader, KindContentType, "content negotiation "+ep.Accept, func() error {
+			return checkNegotiated(resp.Header.Get("Content-Type"), ep.Accept)
+		})
+	}
+
+	if ep.RequireContentType != "" {
+		add(AssertHeader, KindContentType, "content-type "+ep.RequireContentType, func() error {
+			return checkContentType(resp.Header.Get("Content-Type"), ep.RequireContentType)
+		})
+	}
+
+	// Sorted so failures are reported in a stable order
+	for _, name := range slices.Sorted(maps.Keys(ep.HeaderAssert)) {
+		want := ep.HeaderAssert[name]
+		desc := "header " + name + " present"
+		if want != "" {
+			desc = fmt.Sprintf("header %s: %s", name, want)
+		}
+		add(AssertHeader, KindHeader, desc, func() error {
+			return checkHeader(resp.Header, name, want)
+		})
+	}
+
+	if ep.hasBodyAssertions() {
+		add(AssertBody, KindBody, "body", func() error {
+			body, err := body()
+			if err != nil {
+				return err
+			}
+			return checkBody(ep, body)
+		})
+	}
+
+	if ep.MaxLatency > 0 {
+		add(AssertLatency, KindLatency, "latency within "+ep.MaxLatency.String(), func() error {
+			if latency > ep.MaxLatency {
+				return fmt.Errorf("latency %s exceeds max latency %s", latency.Round(time.Millisecond), ep.MaxLatency)
+			}
+			return nil
+		})
+	}
+
+	return list
+}
+
+// checkResponse runs the endpoint's assertions, recording each outcome and the first failure
+// Without assert-all it stops at the first failure
+func (c *Checker) checkResponse(result *Result, ep Endpoint, resp *http.Response, body func() (string, error)) {
+	for _, a := range assertions(ep, resp, body, result.Latency) {
+		err := a.check()
+		outcome := AssertionResult{Type: a.typ, Passed: err == nil, Detail: a.desc}
+		if err != nil {
+			outcome.Detail = err.Error()
+		}
+		result.Assertions = append(result.Assertions, outcome)
+		if err == nil {
+			continue
+		}
+		if result.Error == nil {
+			result.Error = err
+			result.ErrorKind = a.kind
+		}
+		if !c.assertAll {
+			return
+		}
+	}
+}
+
+// checkHeader verifies a response header is present and, unless want is empty, has the value want
+// A header sent several times passes when any of its values matches
+func checkHeader(h http.Header, name, want string) error {
+	values := h.Values(name)
+	if len(values) == 0 {
+		return fmt.Errorf("missing response header %s", name)
+	}
+	if want != "" && !slices.Contains(values, want) {
+		return fmt.Errorf("unexpected response header %s: got %q, expected %q", name, strings.Join(values, ", "), want)
+	}
+	return nil
+}
+
+// formatStatuses lists accepted statuses, e.g. "200", "200 or 204" or "2xx or 304"
+func formatStatuses(statuses []StatusRange) string {
+	parts := make([]string, len(statuses))
+	for i, r := range statuses {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, " or ")
+}
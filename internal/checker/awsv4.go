@@ -0,0 +1,262 @@
+// AWS Signature Version 4
+// Signs requests for SigV4-protected endpoints such as API Gateway
+package checker
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// awsAlgorithm identifies the signing algorithm in the Authorization header
+	awsAlgorithm = "AWS4-HMAC-SHA256"
+
+	// awsDateFormat is the X-Amz-Date timestamp layout
+	awsDateFormat = "20060102T150405Z"
+
+	// defaultIMDSEndpoint is the EC2 instance metadata service, see AWS_EC2_METADATA_SERVICE_ENDPOINT
+	defaultIMDSEndpoint = "http://169.254.169.254"
+
+	// awsCredentialsRefresh is how long before expiry instance credentials are fetched again
+	awsCredentialsRefresh = 5 * time.Minute
+)
+
+// awsCredentials is an AWS access key pair with optional session token
+type awsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// awsCredentials returns credentials from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY (and AWS_SESSION_TOKEN),
+// falling back to the instance role from the EC2 metadata service, cached until shortly before they expire
+func (c *Checker) awsCredentials(ctx context.Context) (awsCredentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return awsCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	c.awsCredsMu.Lock()
+	defer c.awsCredsMu.Unlock()
+	if c.awsCreds != nil && time.Until(c.awsCreds.Expiration) > awsCredentialsRefresh {
+		return *c.awsCreds, nil
+	}
+
+	creds, err := fetchInstanceCredentials(ctx)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials in environment and instance metadata failed: %w", err)
+	}
+	c.awsCreds = &creds
+	return creds, nil
+}
+
+// fetchInstanceCredentials reads the instance role credentials using an IMDSv2 session token
+func fetchInstanceCredentials(ctx context.Context) (awsCredentials, error) {
+	endpoint := strings.TrimSuffix(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), "/")
+	if endpoint == "" {
+		endpoint = defaultIMDSEndpoint
+	}
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	token, err := imdsRequest(ctx, client, http.MethodPut, endpoint+"/latest/api/token",
+		"X-aws-ec2-metadata-token-ttl-seconds", "21600")
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	roles, err := imdsRequest(ctx, client, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/",
+		"X-aws-ec2-metadata-token", token)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	role, _, _ := strings.Cut(strings.TrimSpace(roles), "\n")
+	if role == "" {
+		return awsCredentials{}, fmt.Errorf("instance has no IAM role")
+	}
+	data, err := imdsRequest(ctx, client, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/"+role,
+		"X-aws-ec2-metadata-token", token)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	var creds awsCredentials
+	if err := json.Unmarshal([]byte(data), &creds); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to parse instance credentials: %w", err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("instance credentials for role %q are incomplete", role)
+	}
+	return creds, nil
+}
+
+// imdsRequest performs one metadata service request and returns the response body
+func imdsRequest(ctx context.Context, client *http.Client, method, rawURL, header, value string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(header, value)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: status %d", method, req.URL.Path, resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// signAWSv4 adds X-Amz-Date, the session token and a SigV4 Authorization header to req
+// The host, all X-Amz-* headers and the payload are signed
+func signAWSv4(req *http.Request, payload string, creds awsCredentials, region, service string, now time.Time) {
+	payloadHash := sha256Hex(payload)
+	amzDate := now.UTC().Format(awsDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	if service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	// Canonical headers: lowercase names, sorted, with whitespace-collapsed values
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			trimmed := make([]string, len(values))
+			for i, v := range values {
+				trimmed[i] = strings.Join(strings.Fields(v), " ")
+			}
+			headers[lower] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalPath(req.URL.EscapedPath(), service),
+		awsCanonicalQuery(req.URL.RawQuery),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{awsAlgorithm, amzDate, scope, sha256Hex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsAlgorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// awsCanonicalPath encodes the escaped path once more, except for S3 which signs it as sent
+func awsCanonicalPath(escaped, service string) string {
+	if escaped == "" {
+		return "/"
+	}
+	if service == "s3" {
+		return escaped
+	}
+	segments := strings.Split(escaped, "/")
+	for i, s := range segments {
+		segments[i] = awsURIEncode(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery encodes query parameters and sorts them by name, then value
+func awsCanonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var params [][2]string
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(param, "=")
+		params = append(params, [2]string{awsURIEncode(awsUnescape(name)), awsURIEncode(awsUnescape(value))})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+// awsUnescape decodes a query component, keeping the input as-is when malformed
+func awsUnescape(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// awsURIEncode percent-encodes every byte except the RFC 3986 unreserved characters
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
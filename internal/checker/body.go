@@ -0,0 +1,168 @@
+// Response body assertions
+// Implements reading and validating response bodies
+package checker
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// MaxBodySize is the maximum response body size read for body assertions
+const MaxBodySize = 1 << 20 // 1 MiB
+
+// diffContext is the number of characters shown around a body mismatch
+const diffContext = 20
+
+// WithBodyHash records the SHA-256 of each response body in Result.BodySHA256
+// Bodies are read up to MaxBodySize, as for body assertions
+func WithBodyHash(enabled bool) Option {
+	return func(c *Checker) {
+		c.hashBody = enabled
+	}
+}
+
+// hasBodyAssertions reports whether the endpoint needs the response body
+func (ep Endpoint) hasBodyAssertions() bool {
+	return ep.ExpectedBody != nil || ep.ExpectedBodySHA256 != "" || ep.BodyContains != "" || ep.BodyRegex != nil || ep.JSONAssert != nil || ep.MinBodyBytes > 0 || ep.MaxBodyBytes > 0
+}
+
+// HeadCheck returns a lightweight variant of a GET endpoint with body assertions:
+// HEAD instead of GET, status and header assertions kept, body assertions dropped
+// ok is false, and ep is returned unchanged, when there is no body to skip
+// or when values are captured from the body for later endpoints
+func (ep Endpoint) HeadCheck() (Endpoint, bool) {
+	if !ep.hasBodyAssertions() || ep.method() != http.MethodGet || ep.Body != "" || len(ep.Capture) > 0 {
+		return ep, false
+	}
+
+	ep.Method = http.MethodHead
+	ep.ExpectedBody = nil
+	ep.ExpectedBodySHA256 = ""
+	ep.BodyContains = ""
+	ep.BodyRegex = nil
+	ep.JSONAssert = nil
+	ep.MinBodyBytes = 0
+	ep.MaxBodyBytes = 0
+
+	// A status expected for GET applies to the HEAD sent in its place
+	if code, ok := ep.StatusByMethod[http.MethodGet]; ok {
+		byMethod := maps.Clone(ep.StatusByMethod)
+		if _, exists := byMethod[http.MethodHead]; !exists {
+			byMethod[http.MethodHead] = code
+		}
+		ep.StatusByMethod = byMethod
+	}
+	return ep, true
+}
+
+// readBody reads the response body up to MaxBodySize
+// Gzip bodies are decompressed and the limit applies to the decompressed stream,
+// so a small compressed response cannot expand without bound
+func readBody(resp *http.Response) (string, error) {
+	var r io.Reader = resp.Body
+	decompressed := resp.Uncompressed // Transport already decompressed transparently
+
+	// Custom Accept-Encoding headers disable transparent decompression
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to decompress response body: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+		decompressed = true
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxBodySize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) > MaxBodySize {
+		if decompressed {
+			return "", fmt.Errorf("decompressed body exceeds limit of %d bytes", MaxBodySize)
+		}
+		return "", fmt.Errorf("response body exceeds %d bytes", MaxBodySize)
+	}
+	return string(data), nil
+}
+
+// checkBody runs all configured body assertions
+func checkBody(ep Endpoint, body string) error {
+	if ep.MinBodyBytes > 0 && len(body) < ep.MinBodyBytes {
+		return fmt.Errorf("body too small: got %d bytes, expected at least %d", len(body), ep.MinBodyBytes)
+	}
+	if ep.MaxBodyBytes > 0 && len(body) > ep.MaxBodyBytes {
+		return fmt.Errorf("body too large: got %d bytes, expected at most %d", len(body), ep.MaxBodyBytes)
+	}
+
+	if ep.ExpectedBody != nil {
+		expected := *ep.ExpectedBody
+		got := body
+		if ep.NormalizeWhitespace {
+			expected = normalizeWhitespace(expected)
+			got = normalizeWhitespace(got)
+		}
+		if got != expected {
+			return fmt.Errorf("body does not match expected: %s", bodyDiff(expected, got))
+		}
+	}
+
+	// Load balancers draining a backend may still answer 200 with a body like "DOWN"
+	if ep.BodyContains != "" && !strings.Contains(body, ep.BodyContains) {
+		return fmt.Errorf("body did not contain %q", ep.BodyContains)
+	}
+	if ep.BodyRegex != nil && !ep.BodyRegex.MatchString(body) {
+		return fmt.Errorf("body did not match %q", ep.BodyRegex.String())
+	}
+	if ep.JSONAssert != nil {
+		if err := ep.JSONAssert.check(body); err != nil {
+			return err
+		}
+	}
+
+	if ep.ExpectedBodySHA256 != "" {
+		got := bodySHA256(body)
+		if !strings.EqualFold(got, ep.ExpectedBodySHA256) {
+			return fmt.Errorf("body sha256 mismatch: got %s, expected %s", got, strings.ToLower(ep.ExpectedBodySHA256))
+		}
+	}
+
+	return nil
+}
+
+// bodySHA256 returns the hex SHA-256 of a response body
+func bodySHA256(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeWhitespace collapses whitespace runs into single spaces and trims the ends
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// bodyDiff describes the first difference between expected and actual bodies
+func bodyDiff(expected, got string) string {
+	i := 0
+	for i < len(expected) && i < len(got) && expected[i] == got[i] {
+		i++
+	}
+
+	start := max(i-diffContext, 0)
+	return fmt.Sprintf("at byte %d: expected %q, got %q",
+		i, snippet(expected, start, i+diffContext), snippet(got, start, i+diffContext))
+}
+
+// snippet returns s[start:end] clamped to the string bounds
+func snippet(s string, start, end int) string {
+	if start > len(s) {
+		return ""
+	}
+	return s[start:min(end, len(s))]
+}
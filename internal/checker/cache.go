@@ -0,0 +1,76 @@
+// Cache verification
+// Checks that a repeated request is served from cache
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheResult records the request pair of a cache check
+type CacheResult struct {
+	ColdLatency time.Duration // Latency of the first request
+	WarmLatency time.Duration // Latency of the second request
+	Header      string        // Cache header value on the second response
+	Hit         bool          // Whether the second response was served from cache
+}
+
+// cacheSpeedupFactor is how much faster the server must answer the warm request to count as cached
+// when the cache header does not report a hit
+const cacheSpeedupFactor = 2.0
+
+// WithCacheCheck requests each endpoint twice and requires the second response to be cached
+// A response is cached when header contains "HIT" or the server answered at least twice as fast
+func WithCacheCheck(header string) Option {
+	return func(c *Checker) {
+		c.cacheHeader = header
+	}
+}
+
+// checkCached performs a normal check, then repeats it once and verifies the repeat was cached
+func (c *Checker) checkCached(ctx context.Context, ep Endpoint, spaced bool) Result {
+	cold := c.checkWithRetry(ctx, ep, spaced)
+	if !cold.Healthy || ep.ConnectOnly {
+		return cold
+	}
+
+	warm := c.CheckWithContext(ctx, ep)
+	cache := &CacheResult{
+		ColdLatency: cold.Latency,
+		WarmLatency: warm.Latency,
+	}
+	if warm.Cache != nil {
+		cache.Header = warm.Cache.Header
+	}
+	warm.Cache = cache
+	if !warm.Healthy {
+		return warm
+	}
+
+	// Only the cold request pays for DNS, connect and TLS, so compare the time the server took
+	coldServer, warmServer := cacheServerTime(cold), cacheServerTime(warm)
+	cache.Hit = strings.Contains(strings.ToUpper(cache.Header), "HIT") ||
+		float64(warmServer)*cacheSpeedupFactor <= float64(coldServer)
+	if !cache.Hit {
+		header := cache.Header
+		if header == "" {
+			header = "none"
+		}
+		warm.Healthy = false
+		warm.Error = fmt.Errorf("cache miss: %s %s, warm ttfb %dms vs cold %dms", c.cacheHeader, header,
+			warmServer.Milliseconds(), coldServer.Milliseconds())
+		warm.ErrorKind = KindCache
+	}
+	return warm
+}
+
+// cacheServerTime returns how long the server took to answer: time to first byte,
+// or the whole latency when no phase timings were recorded
+func cacheServerTime(r Result) time.Duration {
+	if r.Timings != nil && r.Timings.TTFB > 0 {
+		return r.Timings.TTFB
+	}
+	return r.Latency
+}
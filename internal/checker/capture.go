@@ -0,0 +1,185 @@
+// Response captures
+// Passes values from one endpoint's JSON response into later endpoints' URL, headers and body
+package checker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// capturePattern matches a {{name}} reference to a captured value
+var capturePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// CaptureRefs returns the captured value names referenced in s, lowercased
+// Names are case-insensitive because config map keys are lowercased
+func CaptureRefs(s string) []string {
+	var names []string
+	for _, m := range capturePattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, strings.ToLower(m[1]))
+	}
+	return names
+}
+
+// captureRefs returns the captured value names used by the endpoint's URL, header values and body
+func (ep Endpoint) captureRefs() []string {
+	refs := CaptureRefs(ep.URL)
+	for _, v := range ep.Headers {
+		refs = append(refs, CaptureRefs(v)...)
+	}
+	return append(refs, CaptureRefs(ep.Body)...)
+}
+
+// capture extracts the endpoint's Capture paths from a JSON response body
+func (ep Endpoint) capture(body string) (map[string]string, error) {
+	captured := make(map[string]string, len(ep.Capture))
+	for name, path := range ep.Capture {
+		value, err := jsonField(body, strings.TrimPrefix(path, "$."))
+		if err != nil {
+			return nil, fmt.Errorf("capture %q: %w", name, err)
+		}
+		captured[strings.ToLower(name)] = value
+	}
+	return captured, nil
+}
+
+// captureRun orders a batch so endpoints using captured values run after the endpoints capturing them
+// A nil captureRun (no captures in the batch) runs every endpoint immediately
+type captureRun struct {
+	deps      [][]int         // Indexes of the endpoints each endpoint waits for
+	done      []chan struct{} // Closed once the endpoint's values are stored
+	producers map[string]int  // Index of the endpoint capturing each name
+	names     []string        // Endpoint names for error messages
+	err       error           // Dependency cycle, failing every endpoint using captures
+
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// newCaptureRun resolves which endpoints capture and use each value
+// It returns nil when no endpoint uses captured values
+func newCaptureRun(endpoints []Endpoint) *captureRun {
+	r := &captureRun{
+		deps:      make([][]int, len(endpoints)),
+		done:      make([]chan struct{}, len(endpoints)),
+		producers: make(map[string]int),
+		names:     make([]string, len(endpoints)),
+		values:    make(map[string]string),
+	}
+	for i, ep := range endpoints {
+		r.done[i] = make(chan struct{})
+		r.names[i] = ep.Name
+		for name := range ep.Capture {
+			r.producers[strings.ToLower(name)] = i
+		}
+	}
+
+	used := false
+	for i, ep := range endpoints {
+		for _, name := range ep.captureRefs() {
+			used = true
+			if j, ok := r.producers[name]; ok {
+				r.deps[i] = append(r.deps[i], j)
+			}
+		}
+	}
+	if !used {
+		return nil
+	}
+	r.err = r.findCycle()
+	return r
+}
+
+// findCycle reports the first endpoint that depends on itself through captured values
+func (r *captureRun) findCycle() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(r.deps))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		state[i] = visiting
+		for _, j := range r.deps[i] {
+			if visit(j) {
+				return true
+			}
+		}
+		state[i] = visited
+		return false
+	}
+	for i := range r.deps {
+		if visit(i) {
+			return fmt.Errorf("endpoint '%s' depends on its own captured values", r.names[i])
+		}
+	}
+	return nil
+}
+
+// prepare waits for the endpoints idx depends on and substitutes their captured values into ep
+func (r *captureRun) prepare(ctx context.Context, idx int, ep Endpoint) (Endpoint, error) {
+	if r == nil || len(ep.captureRefs()) == 0 {
+		return ep, nil
+	}
+	if r.err != nil {
+		return ep, r.err
+	}
+	for _, j := range r.deps[idx] {
+		select {
+		case <-r.done[j]:
+		case <-ctx.Done():
+			return ep, ctx.Err()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var missing error
+	substitute := func(s string) string {
+		return capturePattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := strings.ToLower(capturePattern.FindStringSubmatch(ref)[1])
+			value, ok := r.values[name]
+			if !ok && missing == nil {
+				if j, known := r.producers[name]; known {
+					missing = fmt.Errorf("captured value %q unavailable: endpoint '%s' failed", name, r.names[j])
+				} else {
+					missing = fmt.Errorf("no endpoint captures %q", name)
+				}
+			}
+			return value
+		})
+	}
+
+	ep.URL = substitute(ep.URL)
+	ep.Body = substitute(ep.Body)
+	if len(ep.Headers) > 0 {
+		headers := make(map[string]string, len(ep.Headers))
+		for k, v := range ep.Headers {
+			headers[k] = substitute(v)
+		}
+		ep.Headers = headers
+	}
+	return ep, missing
+}
+
+// finish stores the values captured by endpoint idx and releases the endpoints waiting for it
+func (r *captureRun) finish(idx int, result Result) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	for name, value := range result.Captured {
+		r.values[name] = value
+	}
+	r.mu.Unlock()
+	close(r.done[idx])
+}
@@ -0,0 +1,39 @@
+// Certificate details
+// Records the leaf certificate a TLS endpoint serves, for audits
+package checker
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// CertInfo describes the leaf certificate served by a TLS endpoint
+type CertInfo struct {
+	Subject   string    // Subject distinguished name, e.g. "CN=api.example.com,O=Example"
+	Issuer    string    // Issuer distinguished name
+	DNSNames  []string  // Subject alternative DNS names
+	NotBefore time.Time // Start of the validity period
+	NotAfter  time.Time // Expiry
+}
+
+// WithCertInfo records the served certificate in Result.Cert for HTTPS endpoints
+func WithCertInfo(enabled bool) Option {
+	return func(c *Checker) {
+		c.certInfo = enabled
+	}
+}
+
+// certInfo returns the leaf certificate details of a connection, or nil without TLS
+func certInfo(state *tls.ConnectionState) *CertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+	return &CertInfo{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		DNSNames:  leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+	}
+}
@@ -0,0 +1,58 @@
+// Trust store
+// Builds root CA pools that extend or replace the system trust store
+package checker
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// LoadCertPool returns the system cert pool with the PEM certificates in path appended
+// Public certificates keep verifying while a private CA is also trusted
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	return appendCertsFile(pool, path)
+}
+
+// LoadCACertPool returns a pool holding only the PEM certificates in path
+// Servers must chain to one of them; the system trust store is not consulted
+func LoadCACertPool(path string) (*x509.CertPool, error) {
+	return appendCertsFile(x509.NewCertPool(), path)
+}
+
+// appendCertsFile adds the PEM certificates in path to pool
+func appendCertsFile(pool *x509.CertPool, path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+
+	return pool, nil
+}
+
+// rootCAs returns the pool verifying the endpoint's server, or nil for the system trust store
+// CACert replaces the system trust store and AddCACert extends whichever is in use
+func (ep Endpoint) rootCAs() (*x509.CertPool, error) {
+	switch {
+	case ep.CACert != "" && ep.AddCACert != "":
+		pool, err := LoadCACertPool(ep.CACert)
+		if err != nil {
+			return nil, err
+		}
+		return appendCertsFile(pool, ep.AddCACert)
+	case ep.CACert != "":
+		return LoadCACertPool(ep.CACert)
+	case ep.AddCACert != "":
+		return LoadCertPool(ep.AddCACert)
+	default:
+		return nil, nil
+	}
+}
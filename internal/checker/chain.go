@@ -0,0 +1,63 @@
+// Certificate chain completeness
+// Detects servers that omit intermediate certificates from the TLS handshake
+package checker
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// checkCompleteChain verifies the leaf using only the intermediates the server sent
+// Every certificate of the verified chain except a self-signed root must come from the server,
+// so intermediates in the trust store don't hide the gap (Go, like many mobile clients, never fetches them via AIA)
+// A nil roots pool uses the system trust store
+func checkCompleteChain(peers []*x509.Certificate, roots *x509.CertPool) error {
+	if len(peers) == 0 {
+		return fmt.Errorf("server sent no certificates")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range peers[1:] {
+		intermediates.AddCert(cert)
+	}
+	chains, err := peers[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		// A chain ending in a self-signed certificate is complete, just not trusted
+		var unknown x509.UnknownAuthorityError
+		last := peers[len(peers)-1]
+		if errors.As(err, &unknown) && !isSelfSigned(last) {
+			return fmt.Errorf("incomplete certificate chain: issuer %q of %q not sent by server", last.Issuer.CommonName, last.Subject.CommonName)
+		}
+		return fmt.Errorf("certificate chain does not verify: %w", err)
+	}
+
+	var missing *x509.Certificate
+	for _, chain := range chains {
+		if isSelfSigned(chain[len(chain)-1]) {
+			chain = chain[:len(chain)-1]
+		}
+		i := slices.IndexFunc(chain, func(cert *x509.Certificate) bool {
+			return !slices.ContainsFunc(peers, cert.Equal)
+		})
+		if i < 0 {
+			return nil
+		}
+		if missing == nil {
+			missing = chain[i]
+		}
+	}
+	return fmt.Errorf("incomplete certificate chain: intermediate %q not sent by server", missing.Subject.CommonName)
+}
+
+// isSelfSigned reports whether cert is its own issuer
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
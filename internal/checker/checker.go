@@ -5,23 +5,104 @@ package checker
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"mime"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Checker is the health checker
 type Checker struct {
 	// Cached clients for different configurations
-	// Key format: "secure-follow", "secure-nofollow", "insecure-follow", "insecure-nofollow"
+	// Key format: "secure-follow", "secure-nofollow", "insecure-follow", "insecure-nofollow",
+	// with "-maxredir:<n>", "-sni:<name>", "-cacert:<path>", "-ca:<path>", "-p12:<path>", "-cert:<paths>",
+	// "-proxy:<url>" or "-noproxy", "-resolve:<entries>", "-alpn:<protos>", "-h2" and "-maxhdr:<n>" appended
+	// for redirect limit, TLS server name, CAs, client certificate, proxy, dial overrides, ALPN, HTTP/2
+	// and response header limit
 	clients     map[string]*http.Client
 	clientMu    sync.RWMutex
 	concurrency int
+
+	// Base retry delay and its randomization; rng is shared across goroutines and guarded by rngMu
+	retryDelay  time.Duration
+	retryJitter float64
+	rng         *rand.Rand
+	rngMu       sync.Mutex
+
+	// Only retry failures where no response was received
+	retryNetworkOnly bool
+
+	// Dispatch batch checks in shuffled order, see WithRandomOrder
+	randomOrder bool
+
+	// Adapt concurrency up to the concurrency limit, see WithAutoConcurrency
+	autoConcurrency bool
+
+	// Requests per second sent to each host, see WithRatePerHost; limiters are keyed by host
+	ratePerHost  float64
+	rateLimiters map[string]*rate.Limiter
+	rateMu       sync.Mutex
+
+	// Response header reporting cache status, see WithCacheCheck
+	cacheHeader string
+
+	// Evaluate every assertion, see WithAssertAll
+	assertAll bool
+
+	// User-Agents rotated across requests, see WithUserAgents
+	userAgents []string
+
+	// Resolve hosts before each request, see WithDNSProbe
+	probeDNS bool
+
+	// Record response body hashes, see WithBodyHash
+	hashBody bool
+
+	// Record when each check started and finished, see WithTimestamps
+	timestamps bool
+
+	// Record the served certificate, see WithCertInfo
+	certInfo bool
+
+	// Query OCSP responders for revocation, see WithOCSP
+	ocsp bool
+
+	// Instance role credentials for SigV4 signing, cached until they near expiry
+	awsCreds   *awsCredentials
+	awsCredsMu sync.Mutex
+
+	// Connection reuse counters, see ConnStats
+	connNew    atomic.Int64
+	connReused atomic.Int64
+
+	// Optional request dump destination, see WithRequestDump
+	dumpWriter io.Writer
+	dumpUnmask bool
+	dumpMu     sync.Mutex
+
+	// Record request and response headers in each result, see WithDebug
+	debug       bool
+	debugUnmask bool
 }
 
+// ErrTooManyRedirects is returned when a check would follow more than MaxRedirects redirects
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// defaultRetryDelay is the base delay between retry attempts
+const defaultRetryDelay = 500 * time.Millisecond
+
 // Option is Checker configuration option
 type Option func(*Checker)
 
@@ -34,11 +115,62 @@ func WithConcurrency(n int) Option {
 	}
 }
 
+// WithRetryJitter randomizes each retry delay by up to ±percent (0-100)
+func WithRetryJitter(percent float64) Option {
+	return func(c *Checker) {
+		if percent > 0 && percent <= 100 {
+			c.retryJitter = percent
+		}
+	}
+}
+
+// WithRetryDelayScale multiplies the base retry delay, e.g. for slow environments
+func WithRetryDelayScale(factor float64) Option {
+	return func(c *Checker) {
+		if factor > 0 {
+			c.retryDelay = time.Duration(float64(defaultRetryDelay) * factor)
+		}
+	}
+}
+
+// WithRetryNetworkOnly limits retries to connection-level failures
+// Responses that fail assertions, such as a wrong status code, fail immediately
+func WithRetryNetworkOnly(enabled bool) Option {
+	return func(c *Checker) {
+		c.retryNetworkOnly = enabled
+	}
+}
+
+// WithRandomOrder dispatches batch checks in a random order using the checker's random source
+// Results are still returned in endpoint order
+func WithRandomOrder(enabled bool) Option {
+	return func(c *Checker) {
+		c.randomOrder = enabled
+	}
+}
+
+// WithTimestamps records when each check started and finished in Result.CheckedAt and CompletedAt
+func WithTimestamps(enabled bool) Option {
+	return func(c *Checker) {
+		c.timestamps = enabled
+	}
+}
+
+// WithSeed seeds the random source used for jitter, for reproducible runs
+func WithSeed(seed int64) Option {
+	return func(c *Checker) {
+		c.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
 // New creates a new health checker
 func New(opts ...Option) *Checker {
 	c := &Checker{
-		clients:     make(map[string]*http.Client),
-		concurrency: 10,
+		clients:      make(map[string]*http.Client),
+		concurrency:  10,
+		retryDelay:   defaultRetryDelay,
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		rateLimiters: make(map[string]*rate.Limiter),
 	}
 
 	for _, opt := range opts {
@@ -49,27 +181,69 @@ func New(opts ...Option) *Checker {
 }
 
 // getClientKey generates cache key for client based on endpoint config
-func getClientKey(insecure, followRedirects bool) string {
+func getClientKey(ep Endpoint) string {
 	security := "secure"
-	if insecure {
+	if ep.Insecure {
 		security = "insecure"
 	}
 	redirect := "follow"
-	if !followRedirects {
+	if !ep.FollowRedirects {
 		redirect = "nofollow"
 	}
-	return security + "-" + redirect
+	key := security + "-" + redirect
+	if ep.FollowRedirects && ep.MaxRedirects > 0 {
+		key += fmt.Sprintf("-maxredir:%d", ep.MaxRedirects)
+	}
+	if ep.TLSServerName != "" {
+		key += "-sni:" + ep.TLSServerName
+	}
+	if ep.CACert != "" {
+		key += "-cacert:" + ep.CACert
+	}
+	if ep.AddCACert != "" {
+		key += "-ca:" + ep.AddCACert
+	}
+	if ep.ClientCertP12 != "" {
+		key += "-p12:" + ep.ClientCertP12
+	}
+	if ep.ClientCert != "" || ep.ClientKey != "" {
+		key += "-cert:" + ep.ClientCert + "," + ep.ClientKey
+	}
+	if ep.NoProxy {
+		key += "-noproxy"
+	} else if ep.Proxy != "" {
+		key += "-proxy:" + ep.Proxy
+	}
+	if len(ep.Resolve) > 0 {
+		key += "-resolve:" + strings.Join(ep.Resolve, ",")
+	}
+	if len(ep.TLSALPN) > 0 {
+		key += "-alpn:" + strings.Join(ep.TLSALPN, ",")
+	}
+	if ep.wantsHTTP2() {
+		key += "-h2"
+	}
+	if ep.MaxHeaderBytes > 0 {
+		key += fmt.Sprintf("-maxhdr:%d", ep.MaxHeaderBytes)
+	}
+	return key
+}
+
+// wantsHTTP2 reports whether the endpoint expects an HTTP/2 response or offers h2 via ALPN
+// A custom TLS config disables HTTP/2 unless explicitly requested
+func (ep Endpoint) wantsHTTP2() bool {
+	return strings.HasPrefix(ep.ExpectedProto, "HTTP/2") || slices.Contains(ep.TLSALPN, "h2")
 }
 
 // getClient returns appropriate HTTP client based on endpoint config
-func (c *Checker) getClient(ep Endpoint) *http.Client {
-	key := getClientKey(ep.Insecure, ep.FollowRedirects)
+func (c *Checker) getClient(ep Endpoint) (*http.Client, error) {
+	key := getClientKey(ep)
 
 	// Try to get existing client
 	c.clientMu.RLock()
 	if client, ok := c.clients[key]; ok {
 		c.clientMu.RUnlock()
-		return client
+		return client, nil
 	}
 	c.clientMu.RUnlock()
 
@@ -79,35 +253,72 @@ func (c *Checker) getClient(ep Endpoint) *http.Client {
 
 	// Double check after acquiring write lock
 	if client, ok := c.clients[key]; ok {
-		return client
+		return client, nil
+	}
+
+	// Replace or extend the system trust store with private CAs
+	rootCAs, err := ep.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Present a client certificate for mTLS
+	clientCerts, err := ep.clientCertificates()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := ep.proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	// Dial overridden addresses instead of resolving their hosts
+	dial, err := ep.dialContext(&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	client := &http.Client{
 		Transport: &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
+			Proxy:       proxy,
+			DialContext: dial,
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: ep.Insecure, // #nosec G402 - intentional option for self-signed certs
+				ServerName:         ep.TLSServerName,
+				RootCAs:            rootCAs,
+				Certificates:       clientCerts,
+				NextProtos:         slices.Clone(ep.TLSALPN), // HTTP/2 setup may modify it in place
 			},
-			TLSHandshakeTimeout:   10 * time.Second,
-			ResponseHeaderTimeout: 10 * time.Second,
-			MaxIdleConns:          100,
-			MaxIdleConnsPerHost:   10,
-			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:    10 * time.Second,
+			ResponseHeaderTimeout:  10 * time.Second,
+			MaxResponseHeaderBytes: ep.MaxHeaderBytes,
+			MaxIdleConns:           100,
+			MaxIdleConnsPerHost:    10,
+			IdleConnTimeout:        90 * time.Second,
+			ForceAttemptHTTP2:      ep.wantsHTTP2(),
 		},
 	}
 
-	// Configure redirect handling
+	// Configure redirect handling; without a limit Go stops after 10 redirects
 	if !ep.FollowRedirects {
 		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		}
+	} else if ep.MaxRedirects > 0 {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > ep.MaxRedirects {
+				return fmt.Errorf("%w (>%d)", ErrTooManyRedirects, ep.MaxRedirects)
+			}
+			return nil
+		}
 	}
 
 	c.clients[key] = client
-	return client
+	return client, nil
 }
 
 // Check checks single endpoint health status
@@ -116,60 +327,296 @@ func (c *Checker) Check(ep Endpoint) Result {
 }
 
 // CheckWithContext checks single endpoint with context support
+// With WithRatePerHost the check first waits for its host's turn, outside the endpoint timeout
 func (c *Checker) CheckWithContext(ctx context.Context, ep Endpoint) Result {
-	result := Result{
+	if err := c.waitRate(ctx, ep); err != nil {
+		return rateLimitResult(ctx, ep, err)
+	}
+	return c.checkOnce(ctx, ep)
+}
+
+// checkOnce sends a single request to the endpoint without waiting for the rate limit
+func (c *Checker) checkOnce(ctx context.Context, ep Endpoint) (result Result) {
+	result = Result{
 		Name: ep.Name,
 		URL:  ep.URL,
 	}
+	if c.timestamps {
+		result.CheckedAt = time.Now()
+		defer func() { result.CompletedAt = time.Now() }()
+	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(ctx, ep.Timeout)
 	defer cancel()
+	// Follow-up queries such as OCSP share the timeout but not the request trace
+	queryCtx := ctx
+
+	// Resolve the host first so DNS failures are reported with their own timing
+	if c.probeDNS {
+		if err := probeDNS(ctx, ep, &result); err != nil {
+			result.Error = err
+			result.ErrorKind = KindDNS
+			result.Latency = result.DNSLatency
+			return result
+		}
+	}
 
-	// Get HTTP client
-	client := c.getClient(ep)
+	// Get HTTP client; setup errors such as an unreadable CA file are configuration problems
+	client, err := c.getClient(ep)
+	if err != nil {
+		result.Error = err
+		result.ErrorKind = KindOther
+		return result
+	}
+
+	// Connect-only checks cancel the request once a connection is established
+	ctx, stopRequest := context.WithCancel(ctx)
+	defer stopRequest()
+	var start time.Time
+	var connected atomic.Bool
+	var connectLatency atomic.Int64
+
+	// Trace connection reuse
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				c.connReused.Add(1)
+			} else {
+				c.connNew.Add(1)
+			}
+			if ep.ConnectOnly {
+				connectLatency.Store(int64(time.Since(start)))
+				connected.Store(true)
+				stopRequest()
+			}
+		},
+	}
+	// Connection phases are traced per request, leaving the cached client untouched
+	var phases phaseTrace
+	phases.hook(trace)
+	ctx = httptrace.WithClientTrace(ctx, trace)
 
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.URL, nil)
+	req, err := c.newRequest(ctx, ep)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to create request: %w", err)
+		result.Error = err
+		result.ErrorKind = KindOther
 		return result
 	}
 
-	// Add custom headers
-	for key, value := range ep.Headers {
-		req.Header.Set(key, value)
+	if c.dumpWriter != nil {
+		c.dumpRequest(req)
 	}
-
-	// Set User-Agent
-	if req.Header.Get("User-Agent") == "" {
-		req.Header.Set("User-Agent", "healthcheck-cli/"+Version)
+	if c.debug {
+		result.Debug = c.debugRequest(req)
 	}
 
 	// Execute request and measure time
-	start := time.Now()
+	// A Digest challenge is answered with a second request, included in the latency
+	start = time.Now()
 	resp, err := client.Do(req)
+	if err == nil && ep.AuthType == AuthDigest && resp.StatusCode == http.StatusUnauthorized {
+		resp, err = c.answerDigest(ctx, client, ep, resp)
+	}
 	result.Latency = time.Since(start)
+	result.Timings = phases.timings()
+	if result.Debug != nil && resp != nil {
+		// Report the request that produced the response, e.g. a Digest or redirect follow-up
+		result.Debug = c.debugRequest(resp.Request)
+		c.debugResponse(result.Debug, resp)
+	}
+
+	// The HTTP response is irrelevant once connected
+	if ep.ConnectOnly && connected.Load() {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		result.Latency = time.Duration(connectLatency.Load())
+		result.Healthy = true
+		return result
+	}
 
 	if err != nil {
-		result.Error = c.categorizeError(err)
+		result.ErrorKind, result.Error = c.categorizeError(err)
 		return result
 	}
-	defer resp.Body.Close()
+	defer func() {
+		// Drain unread body so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MaxBodySize))
+		resp.Body.Close()
+	}()
 
-	// Record status code
+	// Record status code, where redirects led, server-reported timings and negotiated protocol
 	result.StatusCode = &resp.StatusCode
+	result.FinalURL, result.Redirects = redirectChain(resp)
+	result.ServerTiming = parseServerTiming(resp.Header)
+	if c.cacheHeader != "" {
+		result.Cache = &CacheResult{Header: resp.Header.Get(c.cacheHeader)}
+	}
+	if len(ep.TLSALPN) > 0 && resp.TLS != nil {
+		result.ALPN = resp.TLS.NegotiatedProtocol
+	}
+	if c.certInfo {
+		result.Cert = certInfo(resp.TLS)
+	}
 
-	// Check if status code matches expected
-	if resp.StatusCode == ep.ExpectedStatus {
-		result.Healthy = true
-	} else {
-		result.Error = fmt.Errorf("unexpected status code: got %d, expected %d", resp.StatusCode, ep.ExpectedStatus)
+	// A revoked certificate fails the check; an unknown status or failed query only warns
+	if c.ocsp && resp.TLS != nil {
+		status, revokedAt, err := queryOCSP(queryCtx, resp.TLS)
+		result.OCSP = status
+		switch {
+		case err != nil:
+			result.Warnings = append(result.Warnings, "OCSP check failed: "+err.Error())
+		case status == OCSPRevoked:
+			result.Error = fmt.Errorf("certificate revoked (OCSP) at %s", revokedAt.Format(time.RFC3339))
+			result.ErrorKind = KindTLS
+			return result
+		case status == OCSPUnknown:
+			result.Warnings = append(result.Warnings, "OCSP responder does not know the certificate")
+		}
+	}
+
+	// The body is read at most once, by body assertions or for hashing
+	body := sync.OnceValues(func() (string, error) { return readBody(resp) })
+
+	// Check status, protocol, redirect, content type, body and latency assertions
+	c.checkResponse(&result, ep, resp, body)
+
+	// Hash the body for change detection, even when an assertion failed
+	// HEAD responses have no body, so they keep the last hash as baseline
+	if c.hashBody && resp.Request.Method != http.MethodHead {
+		if data, err := body(); err == nil {
+			result.BodySHA256 = bodySHA256(data)
+		}
+	}
+	if result.Error != nil {
+		return result
 	}
 
+	// Capture values from the JSON response for later endpoints
+	if len(ep.Capture) > 0 {
+		data, err := body()
+		if err == nil {
+			result.Captured, err = ep.capture(data)
+		}
+		if err != nil {
+			result.Error = err
+			result.ErrorKind = KindBody
+			return result
+		}
+	}
+
+	// Slower than DegradedLatency is degraded but still healthy
+	result.Degraded = ep.DegradedLatency > 0 && result.Latency > ep.DegradedLatency
+
+	result.Healthy = true
 	return result
 }
 
+// newRequest builds the check request with payload, custom headers, User-Agent and Basic or SigV4 credentials
+func (c *Checker) newRequest(ctx context.Context, ep Endpoint) (*http.Request, error) {
+	var body io.Reader
+	if ep.Body != "" {
+		body = strings.NewReader(ep.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, ep.method(), ep.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add custom headers
+	for key, value := range ep.Headers {
+		req.Header.Set(key, value)
+	}
+	if ep.Accept != "" {
+		req.Header.Set("Accept", ep.Accept)
+	}
+
+	// Set User-Agent
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent())
+	}
+
+	switch ep.AuthType {
+	case AuthBasic:
+		req.SetBasicAuth(ep.Username, ep.Password)
+	case AuthAWSv4:
+		creds, err := c.awsCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		signAWSv4(req, ep.Body, creds, ep.AWSRegion, ep.AWSService, time.Now())
+	}
+
+	return req, nil
+}
+
+// redirectChain returns the final URL and the URL of each redirect followed to reach resp
+// Both are empty when the response came from the original request
+func redirectChain(resp *http.Response) (string, []string) {
+	var chain []string
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		chain = append(chain, req.URL.String())
+	}
+	if len(chain) == 0 {
+		return "", nil
+	}
+	slices.Reverse(chain)
+	return chain[len(chain)-1], chain
+}
+
+// checkLocation verifies the redirect Location header against the expected value and pattern
+func checkLocation(location string, ep Endpoint) error {
+	if ep.ExpectedLocation != "" && location != ep.ExpectedLocation {
+		return fmt.Errorf("unexpected redirect location: got %q, expected %q", location, ep.ExpectedLocation)
+	}
+	if ep.LocationPattern != nil && !ep.LocationPattern.MatchString(location) {
+		return fmt.Errorf("unexpected redirect location: got %q, expected match for %q", location, ep.LocationPattern.String())
+	}
+	return nil
+}
+
+// checkContentType verifies the response media type, ignoring parameters such as charset
+func checkContentType(header, expected string) error {
+	if got := responseMediaType(header); !strings.EqualFold(got, expected) {
+		return fmt.Errorf("expected content-type %s, got %s", expected, got)
+	}
+	return nil
+}
+
+// checkNegotiated verifies the response media type matches the one requested with Accept
+// Parameters such as version are sent to the server but not compared
+func checkNegotiated(header, accept string) error {
+	want := accept
+	if mediaType, _, err := mime.ParseMediaType(accept); err == nil {
+		want = mediaType
+	}
+	if got := responseMediaType(header); !strings.EqualFold(got, want) {
+		return fmt.Errorf("content negotiation failed: requested Accept: %s, got Content-Type: %s", accept, got)
+	}
+	return nil
+}
+
+// responseMediaType returns the media type of a Content-Type header without parameters, or "none"
+func responseMediaType(header string) string {
+	if mediaType, _, err := mime.ParseMediaType(header); err == nil {
+		return mediaType
+	}
+	if header == "" {
+		return "none"
+	}
+	return header
+}
+
+// ConnStats returns connection pool statistics accumulated across all checks
+func (c *Checker) ConnStats() ConnStats {
+	return ConnStats{
+		New:    c.connNew.Load(),
+		Reused: c.connReused.Load(),
+	}
+}
+
 // CheckWithRetry performs health check with retry
 func (c *Checker) CheckWithRetry(ep Endpoint) Result {
 	return c.CheckWithRetryContext(context.Background(), ep)
@@ -177,6 +624,12 @@ func (c *Checker) CheckWithRetry(ep Endpoint) Result {
 
 // CheckWithRetryContext performs health check with retry and context
 func (c *Checker) CheckWithRetryContext(ctx context.Context, ep Endpoint) Result {
+	return c.checkWithRetry(ctx, ep, false)
+}
+
+// checkWithRetry performs health check with retry; spaced reports the caller already
+// waited for the host's rate limit on behalf of the first attempt
+func (c *Checker) checkWithRetry(ctx context.Context, ep Endpoint, spaced bool) Result {
 	var result Result
 
 	for i := 0; i <= ep.Retries; i++ {
@@ -184,22 +637,31 @@ func (c *Checker) CheckWithRetryContext(ctx context.Context, ep Endpoint) Result
 		select {
 		case <-ctx.Done():
 			result.Error = ctx.Err()
+			result.ErrorKind = contextErrorKind(ctx.Err())
 			return result
 		default:
 		}
 
-		result = c.CheckWithContext(ctx, ep)
+		if i == 0 && spaced {
+			result = c.checkOnce(ctx, ep)
+		} else {
+			result = c.CheckWithContext(ctx, ep)
+		}
 		if result.Healthy {
 			return result
 		}
+		if c.retryNetworkOnly && !result.ErrorKind.IsNetwork() {
+			return result
+		}
 
 		// Wait before retry if there are more attempts
 		if i < ep.Retries {
 			select {
 			case <-ctx.Done():
 				result.Error = ctx.Err()
+				result.ErrorKind = contextErrorKind(ctx.Err())
 				return result
-			case <-time.After(500 * time.Millisecond):
+			case <-time.After(c.nextRetryDelay()):
 			}
 		}
 	}
@@ -207,6 +669,21 @@ func (c *Checker) CheckWithRetryContext(ctx context.Context, ep Endpoint) Result
 	return result
 }
 
+// nextRetryDelay returns the retry delay with jitter applied
+func (c *Checker) nextRetryDelay() time.Duration {
+	if c.retryJitter == 0 {
+		return c.retryDelay
+	}
+
+	c.rngMu.Lock()
+	r := c.rng.Float64()
+	c.rngMu.Unlock()
+
+	// Scale factor uniformly distributed in [1-jitter, 1+jitter]
+	factor := 1 + (2*r-1)*c.retryJitter/100
+	return time.Duration(float64(c.retryDelay) * factor)
+}
+
 // indexedResult holds result with its original index to preserve order
 // when collecting results from concurrent goroutines.
 type indexedResult struct {
@@ -221,6 +698,12 @@ func (c *Checker) CheckAll(endpoints []Endpoint) BatchResult {
 
 // CheckAllWithContext concurrently checks multiple endpoints with context
 func (c *Checker) CheckAllWithContext(ctx context.Context, endpoints []Endpoint) BatchResult {
+	return c.CheckAllStream(ctx, endpoints, nil)
+}
+
+// CheckAllStream concurrently checks multiple endpoints, calling onResult as each check completes
+// onResult runs on the calling goroutine in completion order; the returned batch keeps endpoint order
+func (c *Checker) CheckAllStream(ctx context.Context, endpoints []Endpoint, onResult func(Result)) BatchResult {
 	startTime := time.Now()
 	results := make([]Result, len(endpoints))
 
@@ -234,31 +717,67 @@ func (c *Checker) CheckAllWithContext(ctx context.Context, endpoints []Endpoint)
 
 	// Use channel for collecting results safely
 	resultChan := make(chan indexedResult, len(endpoints))
-	sem := make(chan struct{}, c.concurrency)
+	var lim limiter = make(semaphore, c.concurrency)
+	var adaptive *adaptiveLimiter
+	if c.autoConcurrency {
+		adaptive = newAdaptiveLimiter(c.concurrency)
+		lim = adaptive
+	}
 	var wg sync.WaitGroup
 
-	for i, ep := range endpoints {
+	// Endpoints using captured values wait for the endpoints capturing them
+	captures := newCaptureRun(endpoints)
+
+	check := c.checkWithRetry
+	if c.cacheHeader != "" {
+		check = c.checkCached
+	}
+
+	for _, i := range c.dispatchOrder(len(endpoints)) {
+		ep := endpoints[i]
 		wg.Add(1)
 		go func(idx int, endpoint Endpoint) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
+			// Substitute captured values before taking a slot, so waiting holds none
+			endpoint, err := captures.prepare(ctx, idx, endpoint)
+			if err != nil {
+				kind := KindOther
+				if ctx.Err() != nil {
+					kind = contextErrorKind(ctx.Err())
+				}
+				captures.finish(idx, Result{})
 				resultChan <- indexedResult{
 					idx:    idx,
-					result: Result{Name: endpoint.Name, URL: endpoint.URL, Error: ctx.Err()},
+					result: Result{Name: endpoint.Name, URL: endpoint.URL, Error: err, ErrorKind: kind},
 				}
 				return
 			}
 
-			// Execute check with retry
-			resultChan <- indexedResult{
-				idx:    idx,
-				result: c.CheckWithRetryContext(ctx, endpoint),
+			// Wait for the host's rate limit before taking a slot, so throttled checks hold none
+			if err := c.waitRate(ctx, endpoint); err != nil {
+				result := rateLimitResult(ctx, endpoint, err)
+				captures.finish(idx, result)
+				resultChan <- indexedResult{idx: idx, result: result}
+				return
 			}
+
+			// Acquire a concurrency slot
+			if !lim.acquire(ctx) {
+				captures.finish(idx, Result{})
+				resultChan <- indexedResult{
+					idx:    idx,
+					result: Result{Name: endpoint.Name, URL: endpoint.URL, Error: ctx.Err(), ErrorKind: contextErrorKind(ctx.Err())},
+				}
+				return
+			}
+
+			// Execute check with retry (and the cached repeat, if enabled)
+			started := time.Now()
+			result := check(ctx, endpoint, true)
+			lim.release(endpoint, result, started)
+			captures.finish(idx, result)
+			resultChan <- indexedResult{idx: idx, result: result}
 		}(i, ep)
 	}
 
@@ -271,36 +790,81 @@ func (c *Checker) CheckAllWithContext(ctx context.Context, endpoints []Endpoint)
 	// Collect results
 	for r := range resultChan {
 		results[r.idx] = r.result
+		if onResult != nil {
+			onResult(r.result)
+		}
+	}
+
+	summary := c.calculateSummary(results, time.Since(startTime))
+	if adaptive != nil {
+		summary.Concurrency = adaptive.Limit()
 	}
 
 	return BatchResult{
 		Timestamp: startTime,
 		Results:   results,
-		Summary:   c.calculateSummary(results, time.Since(startTime)),
+		Summary:   summary,
+	}
+}
+
+// dispatchOrder returns the order in which n endpoints are started
+func (c *Checker) dispatchOrder(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if c.randomOrder {
+		c.rngMu.Lock()
+		c.rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		c.rngMu.Unlock()
 	}
+	return order
 }
 
 // categorizeError categorizes error type
-func (c *Checker) categorizeError(err error) error {
+func (c *Checker) categorizeError(err error) (ErrorKind, error) {
 	errStr := err.Error()
 
 	// Categorize based on error message
 	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		// Server accepted the connection but closed it before responding,
+		// typically while restarting. Treated like any other failure so it is retried.
+		return KindClosed, fmt.Errorf("server closed connection without response: %w", err)
+	case errors.Is(err, ErrTooManyRedirects):
+		// Report the limit without the request URL wrapper
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return KindLocation, err
+	case strings.Contains(errStr, "response headers exceeded"):
+		return KindOther, fmt.Errorf("response headers too large: %w", err)
 	case strings.Contains(errStr, "no such host"):
-		return fmt.Errorf("DNS resolution failed: %w", err)
+		return KindDNS, fmt.Errorf("DNS resolution failed: %w", err)
 	case strings.Contains(errStr, "connection refused"):
-		return fmt.Errorf("connection refused: %w", err)
+		return KindRefused, fmt.Errorf("connection refused: %w", err)
 	case strings.Contains(errStr, "context deadline exceeded"):
-		return fmt.Errorf("connection timeout: %w", err)
+		return KindTimeout, fmt.Errorf("connection timeout: %w", err)
 	case strings.Contains(errStr, "context canceled"):
-		return fmt.Errorf("request canceled: %w", err)
+		return KindCanceled, fmt.Errorf("request canceled: %w", err)
 	case strings.Contains(errStr, "timeout"):
-		return fmt.Errorf("request timeout: %w", err)
+		return KindTimeout, fmt.Errorf("request timeout: %w", err)
 	case strings.Contains(errStr, "certificate"):
-		return fmt.Errorf("SSL certificate error: %w", err)
+		return KindTLS, fmt.Errorf("SSL certificate error: %w", err)
+	case strings.Contains(errStr, "tls:"):
+		return KindTLS, fmt.Errorf("TLS handshake failed: %w", err)
 	default:
-		return err
+		return KindOther, err
+	}
+}
+
+// contextErrorKind classifies a context error
+func contextErrorKind(err error) ErrorKind {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
 	}
+	return KindCanceled
 }
 
 // calculateSummary calculates summary info
@@ -313,9 +877,15 @@ func (c *Checker) calculateSummary(results []Result, duration time.Duration) Sum
 	for _, r := range results {
 		if r.Healthy {
 			summary.Healthy++
+			if r.Degraded {
+				summary.Degraded++
+			}
 		} else {
 			summary.Unhealthy++
 		}
+		if summary.Slowest == nil || r.Latency > summary.Slowest.Latency {
+			summary.Slowest = &Slowest{Name: r.Name, Latency: r.Latency}
+		}
 	}
 
 	return summary
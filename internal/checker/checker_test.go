@@ -3,13 +3,37 @@
 package checker
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"golang.org/x/crypto/ocsp"
 )
 
 // TestDefaultEndpoint tests default endpoint configuration
@@ -77,6 +101,106 @@ func TestWithConcurrency(t *testing.T) {
 	}
 }
 
+// TestWithRetryJitter tests retry jitter configuration option
+func TestWithRetryJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{"valid jitter", 20, 20},
+		{"zero jitter", 0, 0},
+		{"negative jitter", -5, 0},   // Invalid value keeps default
+		{"too large jitter", 150, 0}, // Invalid value keeps default
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithRetryJitter(tt.value))
+			if c.retryJitter != tt.expected {
+				t.Errorf("retryJitter = %v, want %v", c.retryJitter, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCheckWithRetry_NetworkOnly tests that status mismatches are not retried in network-only mode
+func TestCheckWithRetry_NetworkOnly(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, Retries: 2, ExpectedStatus: 200}
+
+	result := New(WithRetryNetworkOnly(true)).CheckWithRetry(ep)
+	if result.Healthy {
+		t.Fatal("Healthy = true, want false")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1 (no retries on status mismatch)", got)
+	}
+
+	// Connection failures are still retried
+	if !KindRefused.IsNetwork() || KindStatus.IsNetwork() || KindTLS.IsNetwork() {
+		t.Error("IsNetwork() classification is wrong for refused/status/tls")
+	}
+}
+
+// TestWithRetryDelayScale tests scaling the base retry delay
+func TestWithRetryDelayScale(t *testing.T) {
+	tests := []struct {
+		factor   float64
+		expected time.Duration
+	}{
+		{2, time.Second},
+		{0.5, 250 * time.Millisecond},
+		{0, defaultRetryDelay},  // Invalid value keeps default
+		{-1, defaultRetryDelay}, // Invalid value keeps default
+	}
+
+	for _, tt := range tests {
+		if d := New(WithRetryDelayScale(tt.factor)).nextRetryDelay(); d != tt.expected {
+			t.Errorf("nextRetryDelay() with scale %v = %v, want %v", tt.factor, d, tt.expected)
+		}
+	}
+}
+
+// TestNextRetryDelay tests jittered retry delays are bounded and reproducible with a seed
+func TestNextRetryDelay(t *testing.T) {
+	if d := New().nextRetryDelay(); d != defaultRetryDelay {
+		t.Errorf("nextRetryDelay() without jitter = %v, want %v", d, defaultRetryDelay)
+	}
+
+	c1 := New(WithRetryJitter(20), WithSeed(42))
+	c2 := New(WithRetryJitter(20), WithSeed(42))
+
+	minDelay := 400 * time.Millisecond
+	maxDelay := 600 * time.Millisecond
+	varied := false
+
+	for i := 0; i < 50; i++ {
+		d1 := c1.nextRetryDelay()
+		d2 := c2.nextRetryDelay()
+
+		if d1 != d2 {
+			t.Fatalf("delay %d: %v != %v with same seed", i, d1, d2)
+		}
+		if d1 < minDelay || d1 > maxDelay {
+			t.Errorf("delay %d = %v, want within [%v, %v]", i, d1, minDelay, maxDelay)
+		}
+		if d1 != defaultRetryDelay {
+			varied = true
+		}
+	}
+
+	if !varied {
+		t.Error("all delays equal base delay, want jitter applied")
+	}
+}
+
 // TestCheck_Success tests successful health check
 func TestCheck_Success(t *testing.T) {
 	// Create mock server
@@ -160,470 +284,3642 @@ func TestCheck_CustomExpectedStatus(t *testing.T) {
 	}
 }
 
-// TestCheck_Timeout tests request timeout
-func TestCheck_Timeout(t *testing.T) {
+// TestCheck_StatusByMethod tests per-method expected status overrides
+func TestCheck_StatusByMethod(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(200 * time.Millisecond)
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusNoContent)
 	}))
 	defer server.Close()
 
-	c := New()
-	ep := Endpoint{
-		Name:           "slow-server",
-		URL:            server.URL,
-		Timeout:        50 * time.Millisecond, // Timeout shorter than server response time
-		ExpectedStatus: 200,
+	tests := []struct {
+		name     string
+		byMethod map[string]int
+		healthy  bool
+	}{
+		{"override matches", map[string]int{"GET": 204}, true},
+		{"other method only", map[string]int{"OPTIONS": 204}, false},
+		{"override mismatches", map[string]int{"GET": 201}, false},
 	}
 
-	result := c.Check(ep)
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := Endpoint{
+				Name:           "test-server",
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				StatusByMethod: tt.byMethod,
+			}
 
-	if result.Healthy {
-		t.Error("Healthy = true, want false (timeout)")
-	}
-	if result.Error == nil {
-		t.Error("Error = nil, want timeout error")
-	}
-	if !strings.Contains(result.Error.Error(), "timeout") {
-		t.Errorf("Error = %q, want to contain 'timeout'", result.Error.Error())
+			result := c.Check(ep)
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+		})
 	}
 }
 
-// TestCheck_CustomHeaders tests custom request headers
-func TestCheck_CustomHeaders(t *testing.T) {
-	var receivedHeaders http.Header
+// TestCheck_ExpectedStatuses tests accepting any status from a set
+func TestCheck_ExpectedStatuses(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedHeaders = r.Header
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusNoContent)
 	}))
 	defer server.Close()
 
-	c := New()
-	ep := Endpoint{
-		Name:           "test-server",
-		URL:            server.URL,
-		Timeout:        5 * time.Second,
-		ExpectedStatus: 200,
-		Headers: map[string]string{
-			"Authorization": "Bearer test-token",
-			"X-Custom":      "custom-value",
-		},
+	tests := []struct {
+		name     string
+		statuses []int
+		ranges   []StatusRange
+		healthy  bool
+		errText  string
+	}{
+		{"in set", []int{200, 204}, nil, true, ""},
+		{"not in set", []int{200, 201}, nil, false, "unexpected status code: got 204, expected 200 or 201"},
+		{"empty set uses expected status", nil, nil, false, "unexpected status code: got 204, expected 200"},
+		{"in class", nil, []StatusRange{{Min: 200, Max: 299}}, true, ""},
+		{"in range", []int{304}, []StatusRange{{Min: 200, Max: 204}}, true, ""},
+		{"not in class or set", []int{304}, []StatusRange{{Min: 500, Max: 599}}, false, "unexpected status code: got 204, expected 304 or 5xx"},
 	}
 
-	c.Check(ep)
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				URL:              server.URL,
+				Timeout:          5 * time.Second,
+				ExpectedStatus:   200,
+				ExpectedStatuses: tt.statuses,
+				ExpectedRanges:   tt.ranges,
+			})
+			if result.Healthy != tt.healthy {
+				t.Fatalf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy && result.Error.Error() != tt.errText {
+				t.Errorf("Error = %q, want %q", result.Error, tt.errText)
+			}
+		})
+	}
+}
 
-	if receivedHeaders.Get("Authorization") != "Bearer test-token" {
-		t.Errorf("Authorization = %q, want %q", receivedHeaders.Get("Authorization"), "Bearer test-token")
+// TestParseStatusRange tests parsing status codes, classes and ranges
+func TestParseStatusRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    StatusRange
+		str     string
+		wantErr bool
+	}{
+		{"200", StatusRange{Min: 200, Max: 200}, "200", false},
+		{"2xx", StatusRange{Min: 200, Max: 299}, "2xx", false},
+		{"5XX", StatusRange{Min: 500, Max: 599}, "5xx", false},
+		{"200-299", StatusRange{Min: 200, Max: 299}, "2xx", false},
+		{"200-204", StatusRange{Min: 200, Max: 204}, "200-204", false},
+		{"2xz", StatusRange{}, "", true},
+		{"0xx", StatusRange{}, "", true},
+		{"500-200", StatusRange{}, "", true},
+		{"200-", StatusRange{}, "", true},
+		{"ok", StatusRange{}, "", true},
 	}
-	if receivedHeaders.Get("X-Custom") != "custom-value" {
-		t.Errorf("X-Custom = %q, want %q", receivedHeaders.Get("X-Custom"), "custom-value")
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStatusRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatusRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want || got.String() != tt.str {
+				t.Errorf("ParseStatusRange(%q) = %+v (%s), want %+v (%s)", tt.input, got, got, tt.want, tt.str)
+			}
+		})
 	}
 }
 
-// TestCheck_UserAgent tests default User-Agent
-func TestCheck_UserAgent(t *testing.T) {
-	var receivedUA string
+// TestCheck_Method tests the request method, including HEAD without a body
+func TestCheck_Method(t *testing.T) {
+	var gotMethod atomic.Value
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedUA = r.Header.Get("User-Agent")
+		gotMethod.Store(r.Method)
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ready"))
 	}))
 	defer server.Close()
 
+	tests := []struct {
+		method  string
+		want    string
+		healthy bool
+	}{
+		{"", "GET", false},
+		{"HEAD", "HEAD", true},
+		{"POST", "POST", true},
+	}
+
 	c := New()
-	ep := Endpoint{
-		Name:           "test-server",
-		URL:            server.URL,
-		Timeout:        5 * time.Second,
-		ExpectedStatus: 200,
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				Name:           "test-server",
+				URL:            server.URL,
+				Method:         tt.method,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+			})
+
+			if got := gotMethod.Load(); got != tt.want {
+				t.Errorf("request method = %v, want %s", got, tt.want)
+			}
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if result.StatusCode == nil || result.Latency <= 0 {
+				t.Errorf("StatusCode = %v, Latency = %v, want both recorded", result.StatusCode, result.Latency)
+			}
+		})
 	}
+}
 
-	c.Check(ep)
+// TestCheck_RequireContentType tests Content-Type verification
+func TestCheck_RequireContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		required    string
+		healthy     bool
+		errContains string
+	}{
+		{"matching type", "application/json", "application/json", true, ""},
+		{"ignores parameters", "application/json; charset=utf-8", "application/json", true, ""},
+		{"case insensitive", "Application/JSON", "application/json", true, ""},
+		{"mismatch", "text/html; charset=utf-8", "application/json", false, "expected content-type application/json, got text/html"},
+		{"missing header", "", "application/json", false, "got none"},
+		{"not required", "text/html", "", true, ""},
+	}
 
-	// User-Agent should be "healthcheck-cli/{version}"
-	expectedPrefix := "healthcheck-cli/"
-	if !strings.HasPrefix(receivedUA, expectedPrefix) {
-		t.Errorf("User-Agent = %q, want prefix %q", receivedUA, expectedPrefix)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header()["Content-Type"] = []string{tt.contentType}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			c := New()
+			ep := Endpoint{
+				Name:               "test-server",
+				URL:                server.URL,
+				Timeout:            5 * time.Second,
+				ExpectedStatus:     200,
+				RequireContentType: tt.required,
+			}
+
+			result := c.Check(ep)
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if tt.errContains != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains)) {
+				t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+			}
+		})
 	}
 }
 
-// TestCheck_NoFollowRedirects tests not following redirects
-func TestCheck_NoFollowRedirects(t *testing.T) {
+// TestCheck_HeaderAssert tests required response headers and their values
+func TestCheck_HeaderAssert(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/redirected", http.StatusMovedPermanently)
+		w.Header().Set("X-Cache", "MISS")
+		w.Header().Add("Via", "1.1 edge-a")
+		w.Header().Add("Via", "1.1 edge-b")
+		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
 
-	c := New()
-	ep := Endpoint{
-		Name:            "redirect-server",
-		URL:             server.URL,
-		Timeout:         5 * time.Second,
-		ExpectedStatus:  301, // Expect redirect status code
-		FollowRedirects: false,
+	tests := []struct {
+		name        string
+		assert      map[string]string
+		healthy     bool
+		errContains string
+	}{
+		{"present", map[string]string{"X-Cache": ""}, true, ""},
+		{"value", map[string]string{"X-Cache": "MISS"}, true, ""},
+		{"any repeated value", map[string]string{"Via": "1.1 edge-b"}, true, ""},
+		{"case insensitive name", map[string]string{"x-cache": "MISS"}, true, ""},
+		{"wrong value", map[string]string{"X-Cache": "HIT"}, false, `unexpected response header X-Cache: got "MISS", expected "HIT"`},
+		{"missing", map[string]string{"X-Gateway": ""}, false, "missing response header X-Gateway"},
 	}
 
-	result := c.Check(ep)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			ep := Endpoint{
+				Name:           "test-server",
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				HeaderAssert:   tt.assert,
+			}
 
-	if !result.Healthy {
-		t.Error("Healthy = false, want true")
-	}
-	if result.StatusCode == nil || *result.StatusCode != 301 {
-		t.Errorf("StatusCode = %v, want 301", result.StatusCode)
+			result := c.Check(ep)
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if tt.errContains != "" {
+				if result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains) {
+					t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+				}
+				if result.ErrorKind != KindHeader {
+					t.Errorf("ErrorKind = %v, want %v", result.ErrorKind, KindHeader)
+				}
+			}
+		})
 	}
 }
 
-// TestCheckWithRetry_SuccessOnFirstTry tests success on first try without retry
-func TestCheckWithRetry_SuccessOnFirstTry(t *testing.T) {
-	callCount := 0
+// TestCheck_Accept tests content negotiation with the Accept shorthand
+func TestCheck_Accept(t *testing.T) {
+	// Serves the JSON:API type only when asked for it
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		callCount++
+		switch r.Header.Get("Accept") {
+		case "application/vnd.api+json", "application/vnd.api+json; version=2":
+			w.Header().Set("Content-Type", "application/vnd.api+json; charset=utf-8")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
 
-	c := New()
-	ep := Endpoint{
-		Name:           "test-server",
-		URL:            server.URL,
-		Timeout:        5 * time.Second,
-		ExpectedStatus: 200,
-		Retries:        3,
+	tests := []struct {
+		name        string
+		accept      string
+		healthy     bool
+		errContains string
+	}{
+		{"negotiated", "application/vnd.api+json", true, ""},
+		{"parameters sent but not compared", "application/vnd.api+json; version=2", true, ""},
+		{"not negotiated", "application/vnd.api+json; version=3", false,
+			"content negotiation failed: requested Accept: application/vnd.api+json; version=3, got Content-Type: application/json"},
+		{"unsupported type", "application/xml", false, "got Content-Type: application/json"},
 	}
 
-	result := c.CheckWithRetry(ep)
-
-	if !result.Healthy {
-		t.Error("Healthy = false, want true")
-	}
-	if callCount != 1 {
-		t.Errorf("callCount = %d, want 1 (no retry needed)", callCount)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New().Check(Endpoint{
+				Name:           "negotiate",
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				Accept:         tt.accept,
+			})
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if tt.errContains != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains)) {
+				t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+			}
+			if !tt.healthy && result.ErrorKind != KindContentType {
+				t.Errorf("ErrorKind = %q, want %q", result.ErrorKind, KindContentType)
+			}
+		})
 	}
 }
 
-// TestCheckWithRetry_SuccessAfterRetry tests success after retry
-func TestCheckWithRetry_SuccessAfterRetry(t *testing.T) {
-	callCount := 0
+// TestCheck_ExpectedBody tests golden body comparison
+func TestCheck_ExpectedBody(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		callCount++
-		if callCount < 3 {
-			w.WriteHeader(http.StatusInternalServerError)
-		} else {
-			w.WriteHeader(http.StatusOK)
-		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("{\n  \"status\": \"ok\",\n  \"version\": \"1.2.3\"\n}\n"))
 	}))
 	defer server.Close()
 
-	c := New()
-	ep := Endpoint{
-		Name:           "flaky-server",
-		URL:            server.URL,
-		Timeout:        5 * time.Second,
-		ExpectedStatus: 200,
-		Retries:        3,
+	tests := []struct {
+		name        string
+		expected    string
+		normalize   bool
+		healthy     bool
+		errContains string
+	}{
+		{"exact match", "{\n  \"status\": \"ok\",\n  \"version\": \"1.2.3\"\n}\n", false, true, ""},
+		{"whitespace differs", `{ "status": "ok", "version": "1.2.3" }`, false, false, "at byte 1"},
+		{"normalized match", `{ "status": "ok",   "version": "1.2.3" }`, true, true, ""},
+		{"content differs", `{ "status": "ok", "version": "1.2.4" }`, true, false, "at byte 34"},
 	}
 
-	result := c.CheckWithRetry(ep)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			ep := Endpoint{
+				Name:                "test-server",
+				URL:                 server.URL,
+				Timeout:             5 * time.Second,
+				ExpectedStatus:      200,
+				ExpectedBody:        &tt.expected,
+				NormalizeWhitespace: tt.normalize,
+			}
 
-	if !result.Healthy {
-		t.Error("Healthy = false, want true")
-	}
-	if callCount != 3 {
-		t.Errorf("callCount = %d, want 3", callCount)
+			result := c.Check(ep)
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if tt.errContains != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains)) {
+				t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+			}
+		})
 	}
 }
 
-// TestCheckWithRetry_AllFailed tests all retries failed
-func TestCheckWithRetry_AllFailed(t *testing.T) {
-	callCount := 0
+// TestCheck_BodyTooLarge tests the body size bound for body assertions
+func TestCheck_BodyTooLarge(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		callCount++
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("x", MaxBodySize+1)))
 	}))
 	defer server.Close()
 
+	expected := "x"
 	c := New()
 	ep := Endpoint{
-		Name:           "bad-server",
+		Name:           "large-server",
 		URL:            server.URL,
 		Timeout:        5 * time.Second,
 		ExpectedStatus: 200,
-		Retries:        2,
+		ExpectedBody:   &expected,
 	}
 
-	result := c.CheckWithRetry(ep)
+	result := c.Check(ep)
 
 	if result.Healthy {
 		t.Error("Healthy = true, want false")
 	}
-	// Initial attempt + 2 retries = 3 times
-	if callCount != 3 {
-		t.Errorf("callCount = %d, want 3", callCount)
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "exceeds") {
+		t.Errorf("Error = %v, want size limit error", result.Error)
 	}
 }
 
-// TestCheckAll tests concurrent batch check
-func TestCheckAll(t *testing.T) {
-	// Create multiple mock servers
-	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+// TestCheck_DecompressedBodyTooLarge tests that gzip bodies are bounded after decompression
+func TestCheck_DecompressedBodyTooLarge(t *testing.T) {
+	// Highly compressible payload that expands well past MaxBodySize
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(bytes.Repeat([]byte("0"), 8*MaxBodySize))
+	_ = gz.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
 		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(compressed.Bytes())
 	}))
-	defer server1.Close()
+	defer server.Close()
+
+	expected := "0"
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"transparent decompression", nil},
+		{"custom Accept-Encoding", map[string]string{"Accept-Encoding": "gzip"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New().Check(Endpoint{
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				Headers:        tt.headers,
+				ExpectedBody:   &expected,
+			})
+			if result.Healthy {
+				t.Fatal("Healthy = true, want false")
+			}
+			if !strings.Contains(result.Error.Error(), "decompressed body exceeds limit") {
+				t.Errorf("Error = %v, want decompressed size limit error", result.Error)
+			}
+		})
+	}
+}
+
+// TestCheck_GzipBody tests body assertions against gzip responses with a custom Accept-Encoding
+func TestCheck_GzipBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"status":"ok"}`))
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	expected := `{"status":"ok"}`
+	result := New().Check(Endpoint{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Headers:        map[string]string{"Accept-Encoding": "gzip"},
+		ExpectedBody:   &expected,
+	})
+	if !result.Healthy {
+		t.Errorf("Healthy = false, error = %v", result.Error)
+	}
+}
+
+// TestCheck_BodySizeRange tests body size bounds
+func TestCheck_BodySizeRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		min, max    int
+		errContains string
+	}{
+		{"within range", "hello world", 10, 1000, ""},
+		{"empty body", "", 10, 1000, "body too small: got 0 bytes, expected at least 10"},
+		{"stack trace", strings.Repeat("x", 1500), 10, 1000, "body too large: got 1500 bytes, expected at most 1000"},
+		{"min only", "ok", 1, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			result := New().Check(Endpoint{
+				Name:           "test-server",
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				MinBodyBytes:   tt.min,
+				MaxBodyBytes:   tt.max,
+			})
+
+			if tt.errContains == "" {
+				if !result.Healthy {
+					t.Errorf("Healthy = false, want true (error: %v)", result.Error)
+				}
+				return
+			}
+			if result.Healthy || !strings.Contains(result.Error.Error(), tt.errContains) {
+				t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+			}
+		})
+	}
+}
+
+// TestCheck_AssertAll tests that assert-all evaluates every assertion
+func TestCheck_AssertAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("maintenance"))
+	}))
+	defer server.Close()
+
+	expected := "ok"
+	ep := Endpoint{
+		Name:               "test-server",
+		URL:                server.URL,
+		Timeout:            5 * time.Second,
+		ExpectedStatus:     200,
+		RequireContentType: "text/plain",
+		ExpectedBody:       &expected,
+	}
+
+	// Default mode stops at the first failure
+	result := New().Check(ep)
+	if result.Healthy || result.ErrorKind != KindStatus {
+		t.Errorf("Healthy = %v, ErrorKind = %q, want false, %q", result.Healthy, result.ErrorKind, KindStatus)
+	}
+	if len(result.Assertions) != 1 {
+		t.Errorf("len(Assertions) = %d, want 1", len(result.Assertions))
+	}
+
+	result = New(WithAssertAll(true)).Check(ep)
+	if result.Healthy || result.ErrorKind != KindStatus {
+		t.Errorf("Healthy = %v, ErrorKind = %q, want false, %q", result.Healthy, result.ErrorKind, KindStatus)
+	}
+
+	want := []AssertionResult{
+		{AssertStatus, false, "unexpected status code: got 503, expected 200"},
+		{AssertHeader, true, "content-type text/plain"},
+		{AssertBody, false, `body does not match expected: at byte 0: expected "ok", got "maintenance"`},
+	}
+	if !reflect.DeepEqual(result.Assertions, want) {
+		t.Errorf("Assertions = %+v, want %+v", result.Assertions, want)
+	}
+}
+
+// TestCheck_Assertions tests that passing checks record every assertion
+func TestCheck_Assertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New().Check(Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		MaxLatency:     time.Minute,
+	})
+	if !result.Healthy {
+		t.Fatalf("Healthy = false, want true (error: %v)", result.Error)
+	}
+
+	want := []AssertionResult{
+		{AssertStatus, true, "status 200"},
+		{AssertLatency, true, "latency within 1m0s"},
+	}
+	if !reflect.DeepEqual(result.Assertions, want) {
+		t.Errorf("Assertions = %+v, want %+v", result.Assertions, want)
+	}
+}
+
+// TestCheck_BodyHash tests body hashing alongside body assertions
+func TestCheck_BodyHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("test"))
+	}))
+	defer server.Close()
+
+	// sha256("test")
+	const digest = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	expected := "test"
+	for _, ep := range []Endpoint{
+		{Name: "plain", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+		{Name: "asserted", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, ExpectedBody: &expected},
+		{Name: "wrong status", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 204},
+	} {
+		result := New(WithBodyHash(true)).Check(ep)
+		if result.BodySHA256 != digest {
+			t.Errorf("%s: BodySHA256 = %q, want %q", ep.Name, result.BodySHA256, digest)
+		}
+	}
+
+	if result := New().Check(Endpoint{Name: "off", URL: server.URL, Timeout: 5 * time.Second}); result.BodySHA256 != "" {
+		t.Errorf("BodySHA256 = %q, want empty without WithBodyHash", result.BodySHA256)
+	}
+}
+
+// TestCheck_ExpectedBodySHA256 tests body checksum assertions
+func TestCheck_ExpectedBodySHA256(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("test"))
+	}))
+	defer server.Close()
+
+	// sha256("test")
+	const digest = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	tests := []struct {
+		name     string
+		sum      string
+		healthy  bool
+		contains string
+	}{
+		{"match", digest, true, ""},
+		{"match uppercase", strings.ToUpper(digest), true, ""},
+		{"mismatch", strings.Repeat("0", 64), false, "body sha256 mismatch: got " + digest},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				URL:                server.URL,
+				Timeout:            5 * time.Second,
+				ExpectedStatus:     200,
+				ExpectedBodySHA256: tt.sum,
+			})
+			if result.Healthy != tt.healthy {
+				t.Fatalf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy && !strings.Contains(result.Error.Error(), tt.contains) {
+				t.Errorf("Error = %q, want to contain %q", result.Error, tt.contains)
+			}
+		})
+	}
+}
+
+// TestCheck_BodyContains tests the body substring assertion and that latency excludes the body read
+func TestCheck_BodyContains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("status: "))
+		if r.URL.Path == "/slow-body" {
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("DOWN"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		contains string
+		healthy  bool
+	}{
+		{"present", "/", "DOWN", true},
+		{"absent", "/", "UP", false},
+		{"present after slow body", "/slow-body", "DOWN", true},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				URL:            server.URL + tt.path,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				BodyContains:   tt.contains,
+			})
+			if result.Healthy != tt.healthy {
+				t.Fatalf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy {
+				if result.ErrorKind != KindBody || result.Error.Error() != `body did not contain "UP"` {
+					t.Errorf("Error = %q (%s), want body did not contain \"UP\"", result.Error, result.ErrorKind)
+				}
+			}
+			if tt.path == "/slow-body" && result.Latency >= 200*time.Millisecond {
+				t.Errorf("Latency = %s, want time to response headers only", result.Latency)
+			}
+		})
+	}
+}
+
+// TestCheck_BodyRegex tests the body pattern assertion
+func TestCheck_BodyRegex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<p>All systems go</p>\n<footer>version: v2.14.3</footer>"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		pattern string
+		healthy bool
+	}{
+		{"match", `version: v\d+\.\d+\.\d+`, true},
+		{"no match", `version: v3\.`, false},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				BodyRegex:      regexp.MustCompile(tt.pattern),
+			})
+			if result.Healthy != tt.healthy {
+				t.Fatalf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy && (result.ErrorKind != KindBody || !strings.Contains(result.Error.Error(), "body did not match")) {
+				t.Errorf("Error = %q (%s), want body pattern mismatch", result.Error, result.ErrorKind)
+			}
+		})
+	}
+}
+
+// TestEndpoint_HeadCheck tests the HEAD variant used between full body checks
+func TestEndpoint_HeadCheck(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte("degraded"))
+	}))
+	defer server.Close()
+
+	ep := Endpoint{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		BodyContains:   "ok",
+		StatusByMethod: map[string]int{"GET": 200},
+	}
+	head, ok := ep.HeadCheck()
+	if !ok {
+		t.Fatal("HeadCheck() ok = false, want true")
+	}
+	if head.Method != http.MethodHead || head.BodyContains != "" || head.StatusByMethod["HEAD"] != 200 {
+		t.Errorf("HeadCheck() = %s, body_contains %q, status_by_method %v", head.Method, head.BodyContains, head.StatusByMethod)
+	}
+	if _, exists := ep.StatusByMethod["HEAD"]; exists {
+		t.Error("HeadCheck() modified the original status_by_method")
+	}
+
+	c := New()
+	if result := c.Check(head); !result.Healthy {
+		t.Errorf("HEAD check Healthy = false, want true (error: %v)", result.Error)
+	}
+	if result := c.Check(ep); result.Healthy {
+		t.Error("GET check Healthy = true, want false")
+	}
+	if len(methods) != 2 || methods[0] != "HEAD" || methods[1] != "GET" {
+		t.Errorf("methods = %v, want [HEAD GET]", methods)
+	}
+
+	for _, other := range []Endpoint{
+		{URL: server.URL},
+		{URL: server.URL, Method: "POST", BodyContains: "ok"},
+		{URL: server.URL, Body: "{}", BodyContains: "ok"},
+		{URL: server.URL, BodyContains: "ok", Capture: map[string]string{"token": "data.token"}},
+	} {
+		if _, ok := other.HeadCheck(); ok {
+			t.Errorf("HeadCheck(%s %q) ok = true, want false", other.method(), other.Body)
+		}
+	}
+}
+
+// TestCheck_JSONAssert tests JSON field lookup and comparison
+func TestCheck_JSONAssert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/text" {
+			_, _ = w.Write([]byte("OK"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"degraded","db":{"up":true,"pool":12},"checks":[{"name":"cache","state":"ok"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		assert  JSONAssert
+		wantErr string
+	}{
+		{"nested bool", "/", JSONAssert{Path: "db.up", Equals: "true"}, ""},
+		{"nested number", "/", JSONAssert{Path: "db.pool", Equals: "12"}, ""},
+		{"array index", "/", JSONAssert{Path: "checks.0.state", Equals: "ok"}, ""},
+		{"mismatch", "/", JSONAssert{Path: "status", Equals: "ok"}, `json field "status": got "degraded", want "ok"`},
+		{"missing field", "/", JSONAssert{Path: "checks.1.state", Equals: "ok"}, `json field "checks.1.state" not found`},
+		{"not json", "/text", JSONAssert{Path: "status", Equals: "ok"}, "response is not JSON"},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := tt.assert
+			result := c.Check(Endpoint{
+				URL:            server.URL + tt.path,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				JSONAssert:     &assert,
+			})
+			if tt.wantErr == "" {
+				if !result.Healthy {
+					t.Errorf("Healthy = false, want true (error: %v)", result.Error)
+				}
+				return
+			}
+			if result.Healthy || result.ErrorKind != KindBody || !strings.Contains(result.Error.Error(), tt.wantErr) {
+				t.Errorf("Error = %v (%s), want body failure containing %q", result.Error, result.ErrorKind, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBodyDiff tests mismatch descriptions
+func TestBodyDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		got      string
+		want     string
+	}{
+		{"differs at start", "abc", "xbc", `at byte 0: expected "abc", got "xbc"`},
+		{"got is prefix", "abcdef", "abc", `at byte 3: expected "abcdef", got "abc"`},
+		{"long context trimmed", strings.Repeat("a", 30) + "X", strings.Repeat("a", 30) + "Y", `at byte 30: expected "` + strings.Repeat("a", 20) + `X", got "` + strings.Repeat("a", 20) + `Y"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bodyDiff(tt.expected, tt.got); got != tt.want {
+				t.Errorf("bodyDiff() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheck_ExpectedLocation tests redirect Location assertions
+func TestCheck_ExpectedLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://secure.example.com/", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		expected    string
+		pattern     string
+		healthy     bool
+		errContains string
+	}{
+		{"exact match", "https://secure.example.com/", "", true, ""},
+		{"exact mismatch", "https://other.example.com/", "", false, `got "https://secure.example.com/", expected "https://other.example.com/"`},
+		{"pattern match", "", "^https://", true, ""},
+		{"pattern mismatch", "", "^http://", false, "expected match for"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := Endpoint{
+				Name:             "redirect",
+				URL:              server.URL,
+				Timeout:          5 * time.Second,
+				ExpectedStatus:   301,
+				FollowRedirects:  false,
+				ExpectedLocation: tt.expected,
+			}
+			if tt.pattern != "" {
+				ep.LocationPattern = regexp.MustCompile(tt.pattern)
+			}
+
+			result := New().Check(ep)
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if tt.errContains != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains)) {
+				t.Errorf("Error = %v, want to contain %q", result.Error, tt.errContains)
+			}
+		})
+	}
+}
+
+// TestCheck_ForbiddenStatus tests negative status assertions
+func TestCheck_ForbiddenStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected int
+		healthy  bool
+	}{
+		{"allowed status", 404, 0, true},
+		{"forbidden status", 502, 0, false},
+		{"forbidden wins over expected", 500, 500, false},
+		{"expected still enforced", 404, 200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ep := Endpoint{
+				Name:            "test-server",
+				URL:             server.URL,
+				Timeout:         5 * time.Second,
+				ExpectedStatus:  tt.expected,
+				ForbiddenStatus: []int{500, 502},
+			}
+
+			result := New().Check(ep)
+
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy && tt.expected == 0 && !strings.Contains(result.Error.Error(), "forbidden status code") {
+				t.Errorf("Error = %v, want forbidden status code error", result.Error)
+			}
+		})
+	}
+}
+
+// TestCheck_Timeout tests request timeout
+func TestCheck_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "slow-server",
+		URL:            server.URL,
+		Timeout:        50 * time.Millisecond, // Timeout shorter than server response time
+		ExpectedStatus: 200,
+	}
+
+	result := c.Check(ep)
+
+	if result.Healthy {
+		t.Error("Healthy = true, want false (timeout)")
+	}
+	if result.Error == nil {
+		t.Error("Error = nil, want timeout error")
+	}
+	if !strings.Contains(result.Error.Error(), "timeout") {
+		t.Errorf("Error = %q, want to contain 'timeout'", result.Error.Error())
+	}
+}
+
+// TestCheck_CustomHeaders tests custom request headers
+func TestCheck_CustomHeaders(t *testing.T) {
+	var receivedHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Headers: map[string]string{
+			"Authorization": "Bearer test-token",
+			"X-Custom":      "custom-value",
+		},
+	}
+
+	c.Check(ep)
+
+	if receivedHeaders.Get("Authorization") != "Bearer test-token" {
+		t.Errorf("Authorization = %q, want %q", receivedHeaders.Get("Authorization"), "Bearer test-token")
+	}
+	if receivedHeaders.Get("X-Custom") != "custom-value" {
+		t.Errorf("X-Custom = %q, want %q", receivedHeaders.Get("X-Custom"), "custom-value")
+	}
+}
+
+// TestCheck_UserAgent tests default User-Agent
+func TestCheck_UserAgent(t *testing.T) {
+	var receivedUA string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	}
+
+	c.Check(ep)
+
+	// User-Agent should be "healthcheck-cli/{version}"
+	expectedPrefix := "healthcheck-cli/"
+	if !strings.HasPrefix(receivedUA, expectedPrefix) {
+		t.Errorf("User-Agent = %q, want prefix %q", receivedUA, expectedPrefix)
+	}
+}
+
+// TestCheck_RotateUserAgent tests User-Agent rotation and explicit header precedence
+func TestCheck_RotateUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Header.Get("User-Agent")] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	agents := []string{"agent-a", "agent-b", "agent-c"}
+	c := New(WithUserAgents(agents), WithSeed(1))
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	}
+	for range 30 {
+		c.Check(ep)
+	}
+
+	if len(seen) != len(agents) {
+		t.Errorf("seen User-Agents = %v, want all of %v", seen, agents)
+	}
+	for ua := range seen {
+		if !slices.Contains(agents, ua) {
+			t.Errorf("unexpected User-Agent %q", ua)
+		}
+	}
+
+	// An explicit header is never rotated
+	clear(seen)
+	ep.Headers = map[string]string{"User-Agent": "custom"}
+	c.Check(ep)
+	if !seen["custom"] || len(seen) != 1 {
+		t.Errorf("seen User-Agents = %v, want only %q", seen, "custom")
+	}
+}
+
+// TestLoadUserAgents tests reading User-Agents from a file
+func TestLoadUserAgents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agents.txt")
+	if err := os.WriteFile(path, []byte("# browsers\nagent-a\n\n  agent-b  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	agents, err := LoadUserAgents(path)
+	if err != nil {
+		t.Fatalf("LoadUserAgents() error = %v", err)
+	}
+	if !slices.Equal(agents, []string{"agent-a", "agent-b"}) {
+		t.Errorf("LoadUserAgents() = %v, want [agent-a agent-b]", agents)
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, []byte("# nothing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadUserAgents(empty); err == nil {
+		t.Error("LoadUserAgents() error = nil, want error for empty file")
+	}
+}
+
+// TestCheck_ProbeDNS tests the DNS pre-check records addresses and fails early
+func TestCheck_ProbeDNS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New(WithDNSProbe(true))
+
+	// IP literal hosts are recorded without a lookup
+	result := c.Check(Endpoint{Name: "ip", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if !result.Healthy || !slices.Equal(result.DNSResolved, []string{"127.0.0.1"}) {
+		t.Errorf("Healthy = %v, DNSResolved = %v, want true, [127.0.0.1]", result.Healthy, result.DNSResolved)
+	}
+
+	// localhost resolves from the hosts file
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	result = c.Check(Endpoint{Name: "localhost", URL: url, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if !result.Healthy || !slices.Contains(result.DNSResolved, "127.0.0.1") {
+		t.Errorf("Healthy = %v, DNSResolved = %v, want true with 127.0.0.1", result.Healthy, result.DNSResolved)
+	}
+
+	// .invalid never resolves (RFC 6761)
+	result = c.Check(Endpoint{Name: "invalid", URL: "http://healthcheck.invalid/", Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if result.Healthy || result.ErrorKind != KindDNS || result.StatusCode != nil {
+		t.Errorf("Healthy = %v, ErrorKind = %q, StatusCode = %v, want false, %q, nil", result.Healthy, result.ErrorKind, result.StatusCode, KindDNS)
+	}
+	if result.Error == nil || !strings.HasPrefix(result.Error.Error(), "DNS resolution failed") {
+		t.Errorf("Error = %v, want DNS resolution failure", result.Error)
+	}
+}
+
+// TestCheck_Timestamps tests per-result start and finish times
+func TestCheck_Timestamps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ep := Endpoint{Name: "test-server", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+
+	before := time.Now()
+	result := New(WithTimestamps(true)).Check(ep)
+	after := time.Now()
+	if result.CheckedAt.Before(before) || result.CompletedAt.After(after) {
+		t.Errorf("CheckedAt = %v, CompletedAt = %v, want within [%v, %v]", result.CheckedAt, result.CompletedAt, before, after)
+	}
+	if elapsed := result.CompletedAt.Sub(result.CheckedAt); elapsed < result.Latency {
+		t.Errorf("CompletedAt - CheckedAt = %v, want at least Latency %v", elapsed, result.Latency)
+	}
+
+	if result := New().Check(ep); !result.CheckedAt.IsZero() || !result.CompletedAt.IsZero() {
+		t.Errorf("CheckedAt = %v, CompletedAt = %v, want zero without WithTimestamps", result.CheckedAt, result.CompletedAt)
+	}
+}
+
+// TestCheck_NoFollowRedirects tests not following redirects
+func TestCheck_NoFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirected", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:            "redirect-server",
+		URL:             server.URL,
+		Timeout:         5 * time.Second,
+		ExpectedStatus:  301, // Expect redirect status code
+		FollowRedirects: false,
+	}
+
+	result := c.Check(ep)
+
+	if !result.Healthy {
+		t.Error("Healthy = false, want true")
+	}
+	if result.StatusCode == nil || *result.StatusCode != 301 {
+		t.Errorf("StatusCode = %v, want 301", result.StatusCode)
+	}
+}
+
+// TestCheckWithRetry_SuccessOnFirstTry tests success on first try without retry
+func TestCheckWithRetry_SuccessOnFirstTry(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Retries:        3,
+	}
+
+	result := c.CheckWithRetry(ep)
+
+	if !result.Healthy {
+		t.Error("Healthy = false, want true")
+	}
+	if callCount != 1 {
+		t.Errorf("callCount = %d, want 1 (no retry needed)", callCount)
+	}
+}
+
+// TestCheckWithRetry_SuccessAfterRetry tests success after retry
+func TestCheckWithRetry_SuccessAfterRetry(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if callCount < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "flaky-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Retries:        3,
+	}
+
+	result := c.CheckWithRetry(ep)
+
+	if !result.Healthy {
+		t.Error("Healthy = false, want true")
+	}
+	if callCount != 3 {
+		t.Errorf("callCount = %d, want 3", callCount)
+	}
+}
+
+// TestCheckWithRetry_AllFailed tests all retries failed
+func TestCheckWithRetry_AllFailed(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "bad-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Retries:        2,
+	}
+
+	result := c.CheckWithRetry(ep)
+
+	if result.Healthy {
+		t.Error("Healthy = true, want false")
+	}
+	// Initial attempt + 2 retries = 3 times
+	if callCount != 3 {
+		t.Errorf("callCount = %d, want 3", callCount)
+	}
+}
+
+// TestCheck_ServerClosedConnection tests a server closing the connection without responding
+func TestCheck_ServerClosedConnection(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("ResponseWriter does not support hijacking")
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			t.Fatalf("Hijack() error = %v", err)
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "closing-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		Retries:        1,
+		ExpectedStatus: 200,
+	}
+
+	result := c.CheckWithRetry(ep)
+
+	if result.Healthy {
+		t.Error("Healthy = true, want false")
+	}
+	if result.Error == nil {
+		t.Fatal("Error = nil, want error")
+	}
+	if !strings.Contains(result.Error.Error(), "server closed connection without response") {
+		t.Errorf("Error = %q, want to contain 'server closed connection without response'", result.Error.Error())
+	}
+	if got := atomic.LoadInt32(&attempts); got < 2 {
+		t.Errorf("attempts = %d, want at least 2 (retry-eligible)", got)
+	}
+}
+
+// TestCheckAll tests concurrent batch check
+func TestCheckAll(t *testing.T) {
+	// Create multiple mock servers
+	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server1.Close()
 
 	server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
-	defer server2.Close()
+	defer server2.Close()
+
+	server3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server3.Close()
+
+	c := New(WithConcurrency(2))
+	endpoints := []Endpoint{
+		{Name: "server1", URL: server1.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+		{Name: "server2", URL: server2.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+		{Name: "server3", URL: server3.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	}
+
+	batch := c.CheckAll(endpoints)
+
+	// Verify result count
+	if len(batch.Results) != 3 {
+		t.Errorf("len(Results) = %d, want 3", len(batch.Results))
+	}
+
+	// Verify result order preserved
+	if batch.Results[0].Name != "server1" {
+		t.Errorf("Results[0].Name = %q, want %q", batch.Results[0].Name, "server1")
+	}
+	if batch.Results[1].Name != "server2" {
+		t.Errorf("Results[1].Name = %q, want %q", batch.Results[1].Name, "server2")
+	}
+	if batch.Results[2].Name != "server3" {
+		t.Errorf("Results[2].Name = %q, want %q", batch.Results[2].Name, "server3")
+	}
+
+	// Verify summary
+	if batch.Summary.Total != 3 {
+		t.Errorf("Summary.Total = %d, want 3", batch.Summary.Total)
+	}
+	if batch.Summary.Healthy != 2 {
+		t.Errorf("Summary.Healthy = %d, want 2", batch.Summary.Healthy)
+	}
+	if batch.Summary.Unhealthy != 1 {
+		t.Errorf("Summary.Unhealthy = %d, want 1", batch.Summary.Unhealthy)
+	}
+}
+
+// TestCheckAll_EmptyEndpoints tests empty endpoint list
+func TestCheckAll_EmptyEndpoints(t *testing.T) {
+	c := New()
+	batch := c.CheckAll([]Endpoint{})
+
+	if len(batch.Results) != 0 {
+		t.Errorf("len(Results) = %d, want 0", len(batch.Results))
+	}
+	if batch.Summary.Total != 0 {
+		t.Errorf("Summary.Total = %d, want 0", batch.Summary.Total)
+	}
+}
+
+// TestCheckAll_RandomOrder tests shuffled dispatch keeps results in endpoint order
+func TestCheckAll_RandomOrder(t *testing.T) {
+	identity := make([]int, 20)
+	for i := range identity {
+		identity[i] = i
+	}
+
+	if order := New().dispatchOrder(20); !reflect.DeepEqual(order, identity) {
+		t.Errorf("dispatchOrder() = %v, want config order", order)
+	}
+
+	first := New(WithRandomOrder(true), WithSeed(42)).dispatchOrder(20)
+	second := New(WithRandomOrder(true), WithSeed(42)).dispatchOrder(20)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave %v and %v, want identical orders", first, second)
+	}
+	if reflect.DeepEqual(first, identity) {
+		t.Error("dispatchOrder() with random order = config order, want shuffled")
+	}
+	sorted := slices.Clone(first)
+	slices.Sort(sorted)
+	if !reflect.DeepEqual(sorted, identity) {
+		t.Errorf("dispatchOrder() = %v, want a permutation of 0..19", first)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	endpoints := make([]Endpoint, 5)
+	for i := range endpoints {
+		endpoints[i] = Endpoint{Name: fmt.Sprintf("ep-%d", i), URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+	}
+	batch := New(WithRandomOrder(true), WithSeed(7)).CheckAll(endpoints)
+	for i, r := range batch.Results {
+		if r.Name != endpoints[i].Name {
+			t.Errorf("Results[%d].Name = %q, want %q", i, r.Name, endpoints[i].Name)
+		}
+	}
+}
+
+// TestCheckAll_AutoConcurrency tests that the adaptive limit ramps up on healthy checks and backs off on timeouts
+func TestCheckAll_AutoConcurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	healthy := make([]Endpoint, 40)
+	for i := range healthy {
+		healthy[i] = Endpoint{Name: fmt.Sprintf("ep-%d", i), URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+	}
+	batch := New(WithConcurrency(8), WithAutoConcurrency(true)).CheckAll(healthy)
+	if batch.Summary.Healthy != 40 {
+		t.Errorf("Summary.Healthy = %d, want 40", batch.Summary.Healthy)
+	}
+	if batch.Summary.Concurrency <= autoConcurrencyStart || batch.Summary.Concurrency > 8 {
+		t.Errorf("Summary.Concurrency = %d, want ramped above %d and at most 8", batch.Summary.Concurrency, autoConcurrencyStart)
+	}
+
+	slow := make([]Endpoint, 4)
+	for i := range slow {
+		slow[i] = Endpoint{Name: fmt.Sprintf("slow-%d", i), URL: server.URL + "/slow", Timeout: 50 * time.Millisecond, ExpectedStatus: 200}
+	}
+	batch = New(WithConcurrency(8), WithAutoConcurrency(true)).CheckAll(slow)
+	if batch.Summary.Concurrency != 1 {
+		t.Errorf("Summary.Concurrency = %d, want 1 after timeouts", batch.Summary.Concurrency)
+	}
+
+	if batch := New(WithConcurrency(8)).CheckAll(healthy); batch.Summary.Concurrency != 0 {
+		t.Errorf("Summary.Concurrency = %d without auto concurrency, want 0", batch.Summary.Concurrency)
+	}
+}
+
+// TestCheckAll_RatePerHost tests requests to one host are spaced out without delaying other hosts,
+// including across batches of the same checker
+func TestCheckAll_RatePerHost(t *testing.T) {
+	var mu sync.Mutex
+	var busyTimes []time.Time
+	var quietAt time.Time
+	busy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		busyTimes = append(busyTimes, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer busy.Close()
+	quiet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		quietAt = time.Now()
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer quiet.Close()
+
+	endpoints := []Endpoint{{Name: "quiet", URL: quiet.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}}
+	for i := range 4 {
+		endpoints = append(endpoints, Endpoint{Name: fmt.Sprintf("busy-%d", i), URL: busy.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	}
+
+	start := time.Now()
+	c := New(WithConcurrency(2), WithRatePerHost(10))
+	batch := c.CheckAll(endpoints)
+	if batch.Summary.Healthy != 5 {
+		t.Fatalf("Summary.Healthy = %d, want 5", batch.Summary.Healthy)
+	}
+	// The next cycle keeps the spacing instead of starting over
+	if result := c.Check(endpoints[1]); !result.Healthy {
+		t.Fatalf("Check() Healthy = false, want true (error: %v)", result.Error)
+	}
+
+	slices.SortFunc(busyTimes, func(a, b time.Time) int { return a.Compare(b) })
+	for i := 1; i < len(busyTimes); i++ {
+		// Starts are 100ms apart; allow for the request reaching the server a little faster
+		if gap := busyTimes[i].Sub(busyTimes[i-1]); gap < 80*time.Millisecond {
+			t.Errorf("gap between busy checks %d and %d = %v, want about 100ms", i-1, i, gap)
+		}
+	}
+	if waited := quietAt.Sub(start); waited > 150*time.Millisecond {
+		t.Errorf("quiet host checked after %v, want without waiting for the busy host", waited)
+	}
+
+	if got := rateLimitHost("https://API.example.com:8443/health"); got != "api.example.com:8443" {
+		t.Errorf("rateLimitHost() = %q, want %q", got, "api.example.com:8443")
+	}
+}
+
+// TestCheckAll_RatePerHostSlow tests a spacing longer than the endpoint timeout does not fail the checks
+func TestCheckAll_RatePerHostSlow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var endpoints []Endpoint
+	for i := range 3 {
+		endpoints = append(endpoints, Endpoint{Name: fmt.Sprintf("ep-%d", i), URL: server.URL, Timeout: 100 * time.Millisecond, ExpectedStatus: 200})
+	}
+
+	// 5 requests per second are 200ms apart, twice the endpoint timeout
+	c := New(WithConcurrency(1), WithRatePerHost(5))
+	batch := c.CheckAll(endpoints)
+	for _, r := range batch.Results {
+		if !r.Healthy {
+			t.Errorf("%s Healthy = false, want true (error: %v)", r.Name, r.Error)
+		}
+	}
+	if batch.Summary.Healthy != 3 {
+		t.Errorf("Summary.Healthy = %d, want 3", batch.Summary.Healthy)
+	}
+}
+
+// TestCheck_LatencyBands tests healthy, degraded and down latency tiers
+func TestCheck_LatencyBands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		degraded time.Duration
+		max      time.Duration
+		state    State
+	}{
+		{"no bands", 0, 0, StateHealthy},
+		{"under degraded", time.Second, 2 * time.Second, StateHealthy},
+		{"degraded", 10 * time.Millisecond, time.Second, StateDegraded},
+		{"over max", 10 * time.Millisecond, 20 * time.Millisecond, StateDown},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				Name:            "test-server",
+				URL:             server.URL,
+				Timeout:         5 * time.Second,
+				ExpectedStatus:  200,
+				DegradedLatency: tt.degraded,
+				MaxLatency:      tt.max,
+			})
+			if result.State() != tt.state {
+				t.Errorf("State() = %q, want %q (error: %v)", result.State(), tt.state, result.Error)
+			}
+			if tt.state == StateDown && result.ErrorKind != KindLatency {
+				t.Errorf("ErrorKind = %q, want %q", result.ErrorKind, KindLatency)
+			}
+		})
+	}
+
+	summary := c.calculateSummary([]Result{{Healthy: true}, {Healthy: true, Degraded: true}, {}}, 0)
+	if summary.Healthy != 2 || summary.Degraded != 1 || summary.Unhealthy != 1 {
+		t.Errorf("Summary = %+v, want 2 healthy (1 degraded), 1 unhealthy", summary)
+	}
+}
+
+// TestCheckAll_Capture tests passing captured response values to later endpoints
+func TestCheckAll_Capture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			time.Sleep(50 * time.Millisecond) // Dependents must wait rather than race ahead
+			_, _ = w.Write([]byte(`{"data":{"token":"abc123","user":{"id":42}}}`))
+		case "/users/42":
+			if r.Header.Get("Authorization") != "Bearer abc123" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ep := func(name, path string) Endpoint {
+		return Endpoint{Name: name, URL: server.URL + path, Timeout: 5 * time.Second, ExpectedStatus: 200}
+	}
+	profile := ep("Profile", "/users/{{id}}")
+	profile.Headers = map[string]string{"Authorization": "Bearer {{TOKEN}}"}
+	login := ep("Login", "/login")
+	login.Capture = map[string]string{"token": "$.data.token", "id": "data.user.id"}
+	broken := ep("Broken", "/broken")
+	broken.Capture = map[string]string{"other": "token"}
+	orphan := ep("Orphan", "/users/{{other}}")
+
+	batch := New().CheckAll([]Endpoint{profile, login, broken, orphan})
+	if !batch.Results[0].Healthy || !batch.Results[1].Healthy {
+		t.Errorf("Profile, Login Healthy = %v, %v, want true (errors: %v, %v)",
+			batch.Results[0].Healthy, batch.Results[1].Healthy, batch.Results[0].Error, batch.Results[1].Error)
+	}
+	if got := batch.Results[1].Captured; !reflect.DeepEqual(got, map[string]string{"token": "abc123", "id": "42"}) {
+		t.Errorf("Captured = %v, want token and id", got)
+	}
+	if r := batch.Results[3]; r.Healthy || r.Error.Error() != `captured value "other" unavailable: endpoint 'Broken' failed` {
+		t.Errorf("Orphan = %v (error: %v), want unavailable capture", r.Healthy, r.Error)
+	}
+
+	// A missing field fails the capturing endpoint
+	login.Capture = map[string]string{"token": "data.missing"}
+	if result := New().Check(login); result.Healthy || result.ErrorKind != KindBody {
+		t.Errorf("missing field: Healthy = %v, ErrorKind = %q, want unhealthy body", result.Healthy, result.ErrorKind)
+	}
+
+	// Cycles fail every endpoint using captured values instead of deadlocking
+	a := ep("A", "/login?b={{b}}")
+	a.Capture = map[string]string{"a": "data.token"}
+	b := ep("B", "/login?a={{a}}")
+	b.Capture = map[string]string{"b": "data.token"}
+	batch = New().CheckAll([]Endpoint{a, b, ep("Plain", "/login")})
+	if batch.Summary.Healthy != 1 || !strings.Contains(batch.Results[0].Error.Error(), "depends on its own captured values") {
+		t.Errorf("cycle: Summary.Healthy = %d, Results[0].Error = %v, want only Plain healthy", batch.Summary.Healthy, batch.Results[0].Error)
+	}
+}
+
+// TestCheckAllStream tests that results are delivered as they complete while the batch keeps order
+func TestCheckAllStream(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+
+	endpoints := []Endpoint{
+		{Name: "slow", URL: slow.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+		{Name: "fast", URL: fast.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	}
+
+	var streamed []string
+	batch := New().CheckAllStream(context.Background(), endpoints, func(r Result) {
+		streamed = append(streamed, r.Name)
+	})
+
+	if !reflect.DeepEqual(streamed, []string{"fast", "slow"}) {
+		t.Errorf("streamed = %v, want completion order [fast slow]", streamed)
+	}
+	if batch.Results[0].Name != "slow" || batch.Results[1].Name != "fast" {
+		t.Errorf("batch order = [%s %s], want config order [slow fast]", batch.Results[0].Name, batch.Results[1].Name)
+	}
+}
+
+// TestCategorizeError tests error categorization
+func TestCategorizeError(t *testing.T) {
+	c := New()
+
+	tests := []struct {
+		name     string
+		err      error
+		kind     ErrorKind
+		contains string
+	}{
+		{"DNS error", errors.New("no such host"), KindDNS, "DNS resolution failed"},
+		{"Connection refused", errors.New("connection refused"), KindRefused, "connection refused"},
+		{"Context deadline", errors.New("context deadline exceeded"), KindTimeout, "connection timeout"},
+		{"Timeout", errors.New("request timeout"), KindTimeout, "timeout"},
+		{"Certificate error", errors.New("certificate verify failed"), KindTLS, "SSL certificate error"},
+		{"TLS handshake", errors.New("remote error: tls: handshake failure"), KindTLS, "TLS handshake failed"},
+		{"EOF", io.EOF, KindClosed, "server closed connection without response"},
+		{"Wrapped EOF", fmt.Errorf("Get \"http://example.com\": %w", io.EOF), KindClosed, "server closed connection without response"},
+		{"Unknown error", errors.New("some random error"), KindOther, "some random error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, result := c.categorizeError(tt.err)
+			if kind != tt.kind {
+				t.Errorf("categorizeError(%q) kind = %q, want %q", tt.err, kind, tt.kind)
+			}
+			if !strings.Contains(result.Error(), tt.contains) {
+				t.Errorf("categorizeError(%q) = %q, want to contain %q", tt.err, result.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+// TestCountByKind tests counting failures by error kind
+func TestCountByKind(t *testing.T) {
+	results := []Result{
+		{Healthy: true},
+		{ErrorKind: KindDNS},
+		{ErrorKind: KindTimeout},
+		{ErrorKind: KindDNS},
+		{ErrorKind: KindNone},
+	}
+
+	counts := CountByKind(results)
+	expected := map[ErrorKind]int{KindDNS: 2, KindTimeout: 1, KindOther: 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("CountByKind() = %v, want %v", counts, expected)
+	}
+}
+
+// TestCheck_ErrorKind tests that failures carry their error kind
+func TestCheck_ErrorKind(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	tests := []struct {
+		name string
+		ep   Endpoint
+		kind ErrorKind
+	}{
+		{"healthy", Endpoint{URL: server.URL, ExpectedStatus: 200}, KindNone},
+		{"status", Endpoint{URL: server.URL, ExpectedStatus: 204}, KindStatus},
+		{"content type", Endpoint{URL: server.URL, ExpectedStatus: 200, RequireContentType: "application/json"}, KindContentType},
+		{"refused", Endpoint{URL: "http://127.0.0.1:1", ExpectedStatus: 200}, KindRefused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ep.Timeout = 5 * time.Second
+			result := c.Check(tt.ep)
+			if result.ErrorKind != tt.kind {
+				t.Errorf("ErrorKind = %q, want %q (error: %v)", result.ErrorKind, tt.kind, result.Error)
+			}
+		})
+	}
+}
+
+// TestCalculateSummary tests summary calculation
+func TestCalculateSummary(t *testing.T) {
+	c := New()
+
+	results := []Result{
+		{Healthy: true},
+		{Healthy: false},
+		{Healthy: true},
+		{Healthy: true},
+		{Healthy: false},
+	}
+
+	summary := c.calculateSummary(results, 100*time.Millisecond)
+
+	if summary.Total != 5 {
+		t.Errorf("Total = %d, want 5", summary.Total)
+	}
+	if summary.Healthy != 3 {
+		t.Errorf("Healthy = %d, want 3", summary.Healthy)
+	}
+	if summary.Unhealthy != 2 {
+		t.Errorf("Unhealthy = %d, want 2", summary.Unhealthy)
+	}
+	if summary.Duration != 100*time.Millisecond {
+		t.Errorf("Duration = %v, want %v", summary.Duration, 100*time.Millisecond)
+	}
+}
+
+// TestCalculateSummary_Empty tests empty result summary
+func TestCalculateSummary_Empty(t *testing.T) {
+	c := New()
+
+	summary := c.calculateSummary([]Result{}, 0)
+
+	if summary.Total != 0 {
+		t.Errorf("Total = %d, want 0", summary.Total)
+	}
+	if summary.Healthy != 0 {
+		t.Errorf("Healthy = %d, want 0", summary.Healthy)
+	}
+	if summary.Unhealthy != 0 {
+		t.Errorf("Unhealthy = %d, want 0", summary.Unhealthy)
+	}
+	if summary.Slowest != nil {
+		t.Errorf("Slowest = %+v, want nil", summary.Slowest)
+	}
+}
+
+// TestCalculateSummary_Slowest tests tracking the endpoint that took the longest
+func TestCalculateSummary_Slowest(t *testing.T) {
+	results := []Result{
+		{Name: "Users", Healthy: true, Latency: 120 * time.Millisecond},
+		{Name: "API Gateway", Healthy: true, Latency: 2300 * time.Millisecond},
+		{Name: "Billing", Latency: 900 * time.Millisecond},
+	}
+
+	summary := New().calculateSummary(results, time.Second)
+	want := &Slowest{Name: "API Gateway", Latency: 2300 * time.Millisecond}
+	if summary.Slowest == nil || *summary.Slowest != *want {
+		t.Errorf("Slowest = %+v, want %+v", summary.Slowest, want)
+	}
+
+	// A timed out endpoint is the bottleneck too
+	results[2] = Result{Name: "Billing", Latency: 5 * time.Second, ErrorKind: KindTimeout}
+	if summary := New().calculateSummary(results, time.Second); summary.Slowest == nil || summary.Slowest.Name != "Billing" {
+		t.Errorf("Slowest = %+v, want Billing", summary.Slowest)
+	}
+}
+
+// TestCheck_InvalidURL tests invalid URL
+func TestCheck_InvalidURL(t *testing.T) {
+	c := New()
+	ep := Endpoint{
+		Name:           "invalid",
+		URL:            "not-a-valid-url",
+		Timeout:        1 * time.Second,
+		ExpectedStatus: 200,
+	}
+
+	result := c.Check(ep)
+
+	if result.Healthy {
+		t.Error("Healthy = true, want false")
+	}
+	if result.Error == nil {
+		t.Error("Error = nil, want error")
+	}
+}
+
+// TestCheckWithContext tests check with context
+func TestCheckWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ctx := context.Background()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	}
+
+	result := c.CheckWithContext(ctx, ep)
+
+	if !result.Healthy {
+		t.Error("Healthy = false, want true")
+	}
+}
+
+// TestCheckWithContext_Cancelled tests context cancellation
+func TestCheckWithContext_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Cancel immediately
+
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	}
+
+	result := c.CheckWithContext(ctx, ep)
+
+	if result.Healthy {
+		t.Error("Healthy = true, want false (cancelled)")
+	}
+	if result.Error == nil {
+		t.Error("Error = nil, want context cancelled error")
+	}
+}
+
+// TestCheckAllWithContext tests batch check with context
+func TestCheckAllWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ctx := context.Background()
+	endpoints := []Endpoint{
+		{Name: "server1", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	}
+
+	batch := c.CheckAllWithContext(ctx, endpoints)
+
+	if len(batch.Results) != 1 {
+		t.Errorf("len(Results) = %d, want 1", len(batch.Results))
+	}
+	if !batch.Results[0].Healthy {
+		t.Error("Results[0].Healthy = false, want true")
+	}
+}
+
+// TestCategorizeError_ContextCanceled tests context canceled error categorization
+func TestCategorizeError_ContextCanceled(t *testing.T) {
+	c := New()
+	err := errors.New("context canceled")
+	_, result := c.categorizeError(err)
+
+	if !strings.Contains(result.Error(), "request canceled") {
+		t.Errorf("categorizeError() = %q, want to contain 'request canceled'", result.Error())
+	}
+}
+
+// TestGetClientKey tests client cache key generation
+func TestGetClientKey(t *testing.T) {
+	tests := []struct {
+		ep       Endpoint
+		expected string
+	}{
+		{Endpoint{FollowRedirects: true}, "secure-follow"},
+		{Endpoint{}, "secure-nofollow"},
+		{Endpoint{Insecure: true, FollowRedirects: true}, "insecure-follow"},
+		{Endpoint{Insecure: true}, "insecure-nofollow"},
+		{Endpoint{FollowRedirects: true, TLSServerName: "tenant.example.com"}, "secure-follow-sni:tenant.example.com"},
+		{Endpoint{FollowRedirects: true, AddCACert: "ca.pem"}, "secure-follow-ca:ca.pem"},
+		{Endpoint{FollowRedirects: true, CACert: "ca.pem"}, "secure-follow-cacert:ca.pem"},
+		{Endpoint{FollowRedirects: true, ClientCert: "a.crt", ClientKey: "a.key"}, "secure-follow-cert:a.crt,a.key"},
+		{Endpoint{FollowRedirects: true, Proxy: "http://proxy:3128"}, "secure-follow-proxy:http://proxy:3128"},
+		{Endpoint{FollowRedirects: true, Proxy: "http://proxy:3128", NoProxy: true}, "secure-follow-noproxy"},
+		{Endpoint{FollowRedirects: true, Resolve: []string{"a.example.com:443:10.0.0.1"}}, "secure-follow-resolve:a.example.com:443:10.0.0.1"},
+		{Endpoint{FollowRedirects: true, ExpectedProto: "HTTP/2.0"}, "secure-follow-h2"},
+		{Endpoint{FollowRedirects: true, ExpectedProto: "HTTP/1.1"}, "secure-follow"},
+		{Endpoint{FollowRedirects: true, MaxHeaderBytes: 4096}, "secure-follow-maxhdr:4096"},
+		{Endpoint{FollowRedirects: true, MaxRedirects: 3}, "secure-follow-maxredir:3"},
+		{Endpoint{MaxRedirects: 3}, "secure-nofollow"},
+		{Endpoint{FollowRedirects: true, TLSALPN: []string{"http/1.1"}}, "secure-follow-alpn:http/1.1"},
+		{Endpoint{FollowRedirects: true, TLSALPN: []string{"h2", "http/1.1"}}, "secure-follow-alpn:h2,http/1.1-h2"},
+	}
+
+	for _, tt := range tests {
+		result := getClientKey(tt.ep)
+		if result != tt.expected {
+			t.Errorf("getClientKey(%+v) = %q, want %q", tt.ep, result, tt.expected)
+		}
+	}
+}
+
+// TestCheck_MaxHeaderBytes tests oversized response headers are rejected
+func TestCheck_MaxHeaderBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Bloat", strings.Repeat("a", 8192))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		MaxHeaderBytes: 1024,
+	}
+
+	result := c.Check(ep)
+	if result.Healthy {
+		t.Fatal("Healthy = true, want false")
+	}
+	if !strings.Contains(result.Error.Error(), "response headers too large") {
+		t.Errorf("Error = %q, want to contain 'response headers too large'", result.Error)
+	}
+
+	ep.MaxHeaderBytes = 0
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("default limit: Healthy = false, want true (error: %v)", result.Error)
+	}
+}
+
+// TestCheck_MaxRedirects tests a redirect chain longer than the limit fails
+func TestCheck_MaxRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hop, _ := strconv.Atoi(r.URL.Query().Get("hop"))
+		if hop < 3 {
+			http.Redirect(w, r, fmt.Sprintf("/?hop=%d", hop+1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:            "test-server",
+		URL:             server.URL,
+		Timeout:         5 * time.Second,
+		ExpectedStatus:  200,
+		FollowRedirects: true,
+		MaxRedirects:    2,
+	}
+
+	result := c.Check(ep)
+	if result.Healthy {
+		t.Fatal("Healthy = true, want false")
+	}
+	if !strings.Contains(result.Error.Error(), "too many redirects (>2)") {
+		t.Errorf("Error = %q, want to contain 'too many redirects (>2)'", result.Error)
+	}
+	if result.ErrorKind != KindLocation {
+		t.Errorf("ErrorKind = %v, want %v", result.ErrorKind, KindLocation)
+	}
+
+	ep.MaxRedirects = 3
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("limit 3: Healthy = false, want true (error: %v)", result.Error)
+	}
+}
+
+// TestCheck_Redirects tests the redirect chain and final URL are recorded
+func TestCheck_Redirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			http.Redirect(w, r, "/auth", http.StatusFound)
+		case "/auth":
+			http.Redirect(w, r, "/login?next=%2F", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{
+		Name:            "test-server",
+		URL:             server.URL + "/",
+		Timeout:         5 * time.Second,
+		ExpectedStatus:  200,
+		FollowRedirects: true,
+	}
+
+	result := c.Check(ep)
+	if !result.Healthy {
+		t.Fatalf("Healthy = false, want true (error: %v)", result.Error)
+	}
+	wantChain := []string{server.URL + "/auth", server.URL + "/login?next=%2F"}
+	if !slices.Equal(result.Redirects, wantChain) {
+		t.Errorf("Redirects = %v, want %v", result.Redirects, wantChain)
+	}
+	if result.FinalURL != wantChain[1] {
+		t.Errorf("FinalURL = %q, want %q", result.FinalURL, wantChain[1])
+	}
+
+	// Nothing is recorded without a redirect
+	ep.URL = server.URL + "/login"
+	if result := c.Check(ep); result.FinalURL != "" || result.Redirects != nil {
+		t.Errorf("no redirect: FinalURL = %q, Redirects = %v, want empty", result.FinalURL, result.Redirects)
+	}
+}
+
+// TestCheckAll_CacheCheck tests the second request must be served from cache
+func TestCheckAll_CacheCheck(t *testing.T) {
+	var requests atomic.Int32
+	warming := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("X-Cache", "MISS")
+		} else {
+			w.Header().Set("X-Cache", "HIT from edge")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer warming.Close()
+
+	uncached := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("X-Cache", "MISS")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer uncached.Close()
+
+	c := New(WithCacheCheck("X-Cache"))
+	batch := c.CheckAll([]Endpoint{
+		{Name: "warming", URL: warming.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+		{Name: "uncached", URL: uncached.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	})
+
+	hit := batch.Results[0]
+	if !hit.Healthy {
+		t.Errorf("warming: Healthy = false, want true (error: %v)", hit.Error)
+	}
+	if hit.Cache == nil || !hit.Cache.Hit || hit.Cache.Header != "HIT from edge" {
+		t.Errorf("warming: Cache = %+v, want hit with header 'HIT from edge'", hit.Cache)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("warming: requests = %d, want 2", requests.Load())
+	}
+
+	miss := batch.Results[1]
+	if miss.Healthy || miss.ErrorKind != KindCache {
+		t.Errorf("uncached: Healthy = %v, ErrorKind = %q, want false, %q", miss.Healthy, miss.ErrorKind, KindCache)
+	}
+	if miss.Cache == nil || miss.Cache.ColdLatency == 0 || miss.Cache.WarmLatency == 0 {
+		t.Errorf("uncached: Cache = %+v, want both latencies recorded", miss.Cache)
+	}
+
+	// Without a hit header, a cold connection's setup doesn't count towards the speedup
+	slow := Result{Latency: 100 * time.Millisecond, Timings: &Timings{Connect: 40 * time.Millisecond, TLS: 50 * time.Millisecond, TTFB: 10 * time.Millisecond}}
+	if got := cacheServerTime(slow); got != 10*time.Millisecond {
+		t.Errorf("cacheServerTime() = %v, want TTFB 10ms", got)
+	}
+	if got := cacheServerTime(Result{Latency: 100 * time.Millisecond}); got != 100*time.Millisecond {
+		t.Errorf("cacheServerTime() without timings = %v, want latency 100ms", got)
+	}
+}
+
+// TestDigestAuthorization tests digest computation against the RFC 2617 example
+func TestDigestAuthorization(t *testing.T) {
+	ch, ok := parseDigestChallenge([]string{
+		`Basic realm="fallback"`,
+		`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+	})
+	if !ok {
+		t.Fatal("parseDigestChallenge() ok = false, want true")
+	}
+
+	auth, err := ch.authorization("Mufasa", "Circle Of Life", "GET", "/dir/index.html", "0a4f113b")
+	if err != nil {
+		t.Fatalf("authorization() error = %v", err)
+	}
+	for _, want := range []string{
+		`response="6629fae49393a05397450978507c4ef1"`,
+		`qop=auth`,
+		`nc=00000001`,
+		`opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+	} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("authorization() = %q, want to contain %q", auth, want)
+		}
+	}
+
+	ch.algorithm = "SHA-512-256"
+	if _, err := ch.authorization("u", "p", "GET", "/", "c"); err == nil {
+		t.Error("authorization() with unsupported algorithm error = nil, want error")
+	}
+}
+
+// TestCheck_DigestAuth tests the Digest challenge-response flow
+func TestCheck_DigestAuth(t *testing.T) {
+	const realm, nonce = "appliance", "abc123"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+		ch := digestChallenge{realm: realm, nonce: nonce, qop: "auth", algorithm: "SHA-256"}
+		want, _ := ch.authorization("monitor", "secret", r.Method, r.URL.RequestURI(), params["cnonce"])
+		if r.Header.Get("Authorization") != want {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="%s", nonce="%s", qop="auth", algorithm=SHA-256`, realm, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		healthy  bool
+	}{
+		{"valid credentials", "secret", true},
+		{"wrong password", "wrong", false},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				Name:           "test-server",
+				URL:            server.URL + "/status?x=1",
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				AuthType:       AuthDigest,
+				Username:       "monitor",
+				Password:       tt.password,
+			})
+			if result.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+		})
+	}
+}
+
+// TestCheck_BasicAuth tests Basic credentials are sent
+func TestCheck_BasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "monitor" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	result := c.Check(Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		AuthType:       AuthBasic,
+		Username:       "monitor",
+		Password:       "secret",
+	})
+	if !result.Healthy {
+		t.Errorf("Healthy = false, want true (error: %v)", result.Error)
+	}
+}
+
+// TestCheck_RequestBody tests that the configured payload is sent
+func TestCheck_RequestBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(data) != `{"q":"health"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New().Check(Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Method:         http.MethodPost,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Body:           `{"q":"health"}`,
+	})
+	if !result.Healthy {
+		t.Errorf("Healthy = false, want true (error: %v)", result.Error)
+	}
+}
+
+// TestSignAWSv4 tests SigV4 signatures against the AWS signature test suite
+func TestSignAWSv4(t *testing.T) {
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		url       string
+		signature string
+	}{
+		{"get-vanilla", "https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-query-order-key-case", "https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signAWSv4(req, "", creds, "us-east-1", "service", now)
+
+			want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tt.signature
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Errorf("Authorization = %q, want %q", got, want)
+			}
+			if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %q, want 20150830T123600Z", got)
+			}
+		})
+	}
+}
+
+// TestCheck_AWSv4 tests signing with environment credentials and the instance metadata fallback
+func TestCheck_AWSv4(t *testing.T) {
+	var gotAuth, gotToken atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth.Store(r.Header.Get("Authorization"))
+		gotToken.Store(r.Header.Get("X-Amz-Security-Token"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
 
-	server3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var imdsCalls atomic.Int32
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imdsCalls.Add(1)
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			fmt.Fprint(w, "imds-token")
+		case r.Header.Get("X-aws-ec2-metadata-token") != "imds-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			fmt.Fprint(w, "monitor-role\n")
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/monitor-role":
+			fmt.Fprintf(w, `{"AccessKeyId":"ASIAROLE","SecretAccessKey":"role-secret","Token":"role-token","Expiration":%q}`,
+				time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer imds.Close()
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", imds.URL)
+
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL + "/prod/health",
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		AuthType:       AuthAWSv4,
+		AWSRegion:      "eu-west-1",
+		AWSService:     "execute-api",
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	if result := New().Check(ep); !result.Healthy {
+		t.Fatalf("Healthy = false, want true (error: %v)", result.Error)
+	}
+	if auth := gotAuth.Load().(string); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDENV/") || !strings.Contains(auth, "/eu-west-1/execute-api/aws4_request") {
+		t.Errorf("Authorization = %q, want SigV4 with environment key and scope", auth)
+	}
+	if imdsCalls.Load() != 0 {
+		t.Errorf("instance metadata calls = %d with environment credentials, want 0", imdsCalls.Load())
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	c := New()
+	for range 2 {
+		if result := c.Check(ep); !result.Healthy {
+			t.Fatalf("Healthy = false, want true (error: %v)", result.Error)
+		}
+	}
+	if auth := gotAuth.Load().(string); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=ASIAROLE/") || !strings.Contains(auth, "x-amz-security-token") {
+		t.Errorf("Authorization = %q, want SigV4 with instance role key and signed token", auth)
+	}
+	if token := gotToken.Load().(string); token != "role-token" {
+		t.Errorf("X-Amz-Security-Token = %q, want role-token", token)
+	}
+	if imdsCalls.Load() != 3 {
+		t.Errorf("instance metadata calls = %d, want 3 (credentials cached)", imdsCalls.Load())
+	}
+
+	imds.Close()
+	if result := New().Check(ep); result.Healthy || !strings.Contains(fmt.Sprint(result.Error), "no AWS credentials") {
+		t.Errorf("Error = %v, want missing credentials failure", result.Error)
+	}
+}
+
+// PKCS#12 bundles for CN=healthcheck-client (P-256 key, password "s3cret")
+// generated with OpenSSL 3 defaults (PBES2/AES-256), with -certpbe/-keypbe PBE-SHA1-3DES -macalg sha1
+// and with -certpbe PBE-SHA1-RC2-40 -keypbe PBE-SHA1-3DES, the layout of Windows and Java exports
+const (
+	testP12Modern = `
+MIIEHAIBAzCCA9IGCSqGSIb3DQEHAaCCA8MEggO/MIIDuzCCAnIGCSqGSIb3DQEHBqCCAmMwggJf
+AgEAMIICWAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAhHi/pVdDdA
+TQICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEA1Vo6YtSYW5/yZ/ViRRfAyAggHwLwA8
+QIcwtfdSZ3QNxdtU0B2Juo87uWum1odYWAKK8wNOmf/NFqk9n+5wo9rCucZIapO4TdhHOhLUW3ZC
+Pk4ySrEH8mJtZVK9VFUAFBLBvrQr+RRu03JThS8IuQuZ+sDL/8IhlkBpsnXEYAOJLACJTqbf1rGQ
+Vyh29MYzlrjTDrnS5uIIYDAuL7cjZY5s2Ft7iKeSdIBsjrnJ0cRpvDwa+xaO8MYQYZmuEdCWrq0b
+n26FfyyUcy9qo7wFqzoD/g0GncMSBZjkGWLvtmpdrOA0O5jPUrvfO6m4dYqPO402Sl966ufu2RSR
+iesRu9jCIBfKCqjDPQAXTWHcQRZs43CjVu9FPwltwlHTpWMjmYVakiWj9QRXHy0p/szGo7GOfZLB
+2dnj1u4HaNp6nRSF7ToqcZ12W6YgH3FKJFJ13voFr25WcDR2iCAqa9x3wdsaF3NYtFP0wi/j7NuJ
+dW5GEGBv3nBaCp+xAV/z9OuKzhMMFvZs5dpvYTgczUw8z29XUYDOCMvcPJ088Gn7fvrCIQRHocnD
+pnvvh8vPyZ7Hj5mapZTkEtZmIY64Ue9RFfeg3CNmHPep1St4D6fsZoOnH0DJZgPJTE+6Q2X/ofOG
+/ZDJAXZDT3kEjjthRrUJZGjgYWghtHW+dk1L0KARqtM8KAJ3rDCCAUEGCSqGSIb3DQEHAaCCATIE
+ggEuMIIBKjCCASYGCyqGSIb3DQEMCgECoIHvMIHsMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEF
+DDAcBAhkUZZufpbQbgICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEGt+PDmjW/SFUL0z
+I0jXse8EgZBUDu+uWeRl9lnnGUXhET7s9XgyKWVmc7AOPYafpdg4XVJud2Vf4qVNN8virlogoxlo
+waHFVR/l2OwNVYJrmNVEX4/KSWKfC+HeASFJAcfqm1XxOdAAXOrGiONkY5Eo+DqsCue+xBD/H6lk
+X/qp2QOVyMNV52/yZiZONJwHOswx6UTkAKQipq+8PVmo4EQ1ZkgxJTAjBgkqhkiG9w0BCRUxFgQU
+EQR70GF9bTNP6JLj5rHOLmarQrIwQTAxMA0GCWCGSAFlAwQCAQUABCA4D8iK9Aizb7cbP7mgvIxS
+tI1WLsuEhLYP/mL8Ry1xPQQIUH1G2W5eqpsCAggA`
+	testP12Legacy = `
+MIIDkgIBAzCCA1gGCSqGSIb3DQEHAaCCA0kEggNFMIIDQTCCAjcGCSqGSIb3DQEHBqCCAigwggIk
+AgEAMIICHQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQMwDgQIw1iONbB13FMCAggAgIIB8ACWu2sI
+F/4yDialIN6z9yHlr6It1UD1MLqefUMdNzAZDP7fOtpk5gPsycWqBt2s0WoXeYgQbzEswARVbTXT
+yYyWhe4tf/lxFKlF8scKiFJUhYIontdLM4er/uTB/bt8Kac6E5iIfkYjj0fNLUKHSagrwUgq/P31
++7oLcRSQhDE1ap8rAoV69nD3qSt/t0e931q5WuTGdJxocuHRjhwrgjo0+y6Moky7JRYiX9iZl9dE
+6kctoJ/kFngxYbRm2+5RkHESyAIoqR5el7Hbz34avMGMeIFfibgKT6ho7iYl1qmhoyAEpP8WfUxx
+BKPakW5MvtSAnrm5IKAKlE5C4vLgB6m2PTORhXQMqZvf8Hd6v7gPw1w21t/vLHuxownstPunlLi/
+RCr1leayx1FBrXhm0RnlrPRD2NgJlM0GKNye2A0OboP0n0PoJ0CZl/moFQkiGCkD/qp0nSfm5frF
+T79DcbKqonk0QsqNm/MTc/ueP1HuIo193JHMk81D5QOzH6fNLTkl5FUl7+5IFQKY5FWbXUWIf1Pk
+vEz45ygJTPSQmcj6jLJPLx2K0jvbbzqCU7eDQENJsubeQfDELdll7VsQN1uE36WAeTTSeSZT+54p
+hr2Hf/kCJVWXposVn864mB9DU/njkCS/6FMp6Obn0yyCT20wggECBgkqhkiG9w0BBwGggfQEgfEw
+ge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQIGPqknvxurYQCAggABIGQ
+S0VAmZQ8nioC0OLyfJ9YR/CTZU3lMU78PCEFYbwwpJZNgsm0BCj52aOnI9g7oE4UChch9IVr0kkA
+P0Q6GL6GPRAGsOS0dVEdP4nuaEQ+kQ6C4j7EsDjDBB/CS99djmfcHCeBiO3Id4+NdNdHBBkQbgW0
+gL1YKT/C1tZy/aVGfxtzdh+h3Masolg0id7xBmbHMSUwIwYJKoZIhvcNAQkVMRYEFBEEe9BhfW0z
+T+iS4+axzi5mq0KyMDEwITAJBgUrDgMCGgUABBT6SAmT0z+QAcDq5mBi5xC7+GFmXAQIeck7K4sF
+dSQCAggA`
+	testP12RC2 = `
+MIIDkgIBAzCCA1gGCSqGSIb3DQEHAaCCA0kEggNFMIIDQTCCAjcGCSqGSIb3DQEHBqCCAigwggIk
+AgEAMIICHQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQIn2c5XQKjk2UCAggAgIIB8Oe2ZhKo
+b01V+t2M3Soxg+AZPlI9fp/TxvXN+UCKsfFiReDNI4wPLifDqOKFXG/p34tUMaSSvC+WNF2DQFJz
+ytKjwfwl3yq2x14NChkd30jyZpBpBfcR6tv1eAkTUJyh6Qhg9KU2gNAVFm4Ky/RtlLtcwU7A215U
+VwvbIT8JOW3nj9RaWbR320UFRhaBxwA7rM2lHD/61UGskYI79R1Cr0TFrvryfJoDdwgc9vnpaHBV
+bbXP70JiaRMhr8AoT8ez224OYH5G3vsDqH4Ogreq45+9EtPlqqB1M2ycTNJqky2EeJ1AUWU8HxmB
+PrKQFoyTz84gDV5mNpc2Lbr3evYQZTk3c+zEiuyR/GuMwjHTMFpYGKXsWfW04z7flAI8eLHGMAG6
+S6Jw/yyvRdGog+DpSIsu3ViOxChzHaJimCiO0kaP3lxKVJtOo1iUGgrb0Kw/AfLAbkn2lVmuDCAm
+/lrO2iT72lq2vVvIjrQNzBWhSl/hjIAvVmHScWWkVeRIrLCAXtQgFGfb9pPU7auAyzKND+7eAH1S
+SzN797d7l1rKLFcbOlTA2GePq08P5ZHU33gtcSwJ4pxhwwmV4G43pSOTBL09MCAVZeBZxEKr7hXI
+JC/tsfGMMaXU2SbZmEDnSOvuI4x74g9wAteBYSUtFp9bHWAwggECBgkqhkiG9w0BBwGggfQEgfEw
+ge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQImTQLrOg7IPACAggABIGQ
+4V+K3Cmpi/OhHiHEnZzMdnae8VENEq1SL//FYVyxGXH/NSmInThaDKUp8v4BchnrMsWx2Vt1+tLa
+c/VoZnB2a6ZGuqS0Mqt8D/Iy5jO/JY2nU9riHACBi7TN+vlJxvHxiN8QOcKd9ZwXdwXHr3gpaa9A
+AdOoEi0h/mh9fxRluzNsvdR7sN7cCtWxNdfveCHDMSUwIwYJKoZIhvcNAQkVMRYEFBEEe9BhfW0z
+T+iS4+axzi5mq0KyMDEwITAJBgUrDgMCGgUABBTxJAcaUSlI52SrP/2/ot9CyMaV5AQIRkXxEAH1
+cbQCAggA`
+)
+
+// writeTestP12 decodes a base64 PKCS#12 fixture into a temporary file
+func writeTestP12(t *testing.T, encoded string) string {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "client.p12")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return path
+}
+
+// TestLoadPKCS12 tests decoding PKCS#12 client certificate bundles
+func TestLoadPKCS12(t *testing.T) {
+	for name, fixture := range map[string]string{"pbes2": testP12Modern, "3des": testP12Legacy, "rc2": testP12RC2} {
+		t.Run(name, func(t *testing.T) {
+			path := writeTestP12(t, fixture)
+
+			cert, err := LoadPKCS12(path, "s3cret")
+			if err != nil {
+				t.Fatalf("LoadPKCS12() error = %v", err)
+			}
+			if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "healthcheck-client" {
+				t.Errorf("Leaf = %v, want CN=healthcheck-client", cert.Leaf)
+			}
+			if len(cert.Certificate) != 1 || cert.PrivateKey == nil {
+				t.Errorf("Certificate = %d certs, PrivateKey = %v, want 1 cert with key", len(cert.Certificate), cert.PrivateKey)
+			}
+
+			if _, err := LoadPKCS12(path, "wrong"); err == nil || !strings.Contains(err.Error(), "incorrect password") {
+				t.Errorf("LoadPKCS12(wrong password) error = %v, want incorrect password", err)
+			}
+		})
+	}
+
+	garbage := filepath.Join(t.TempDir(), "garbage.p12")
+	if err := os.WriteFile(garbage, []byte("not a bundle"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPKCS12(garbage, ""); err == nil || !strings.Contains(err.Error(), "invalid PKCS#12 bundle") {
+		t.Errorf("LoadPKCS12(garbage) error = %v, want invalid bundle", err)
+	}
+}
+
+// TestCheck_ClientCertP12 tests presenting a PKCS#12 client certificate for mTLS
+func TestCheck_ClientCertP12(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "healthcheck-client" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
-	defer server3.Close()
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
 
-	c := New(WithConcurrency(2))
-	endpoints := []Endpoint{
-		{Name: "server1", URL: server1.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
-		{Name: "server2", URL: server2.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
-		{Name: "server3", URL: server3.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	path := writeTestP12(t, testP12Modern)
+	c := New()
+	ep := Endpoint{
+		Name:           "test-server",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Insecure:       true,
+	}
+
+	if result := c.Check(ep); result.Healthy {
+		t.Error("without client cert: Healthy = true, want false")
+	}
+
+	ep.ClientCertP12 = path
+	ep.ClientCertP12Pass = "s3cret"
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("with client cert: Healthy = false, want true (error: %v)", result.Error)
+	}
+}
+
+// TestCheck_ClientCert tests presenting a PEM client certificate and key for mTLS
+func TestCheck_ClientCert(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "healthcheck-client" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeIdentity := func(name string) (string, string) {
+		cert, key := newTestCert(t, name, false, nil, nil)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("MarshalECPrivateKey() error = %v", err)
+		}
+		certPath := filepath.Join(dir, name+".crt")
+		keyPath := filepath.Join(dir, name+".key")
+		if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return certPath, keyPath
+	}
+	clientCert, clientKey := writeIdentity("healthcheck-client")
+	otherCert, otherKey := writeIdentity("someone-else")
+
+	// One checker, so differing identities must not share a cached client
+	c := New()
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, Insecure: true}
+
+	ep.ClientCert, ep.ClientKey = clientCert, clientKey
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("client cert: Healthy = false, error = %v", result.Error)
+	}
+
+	ep.ClientCert, ep.ClientKey = otherCert, otherKey
+	if result := c.Check(ep); result.Healthy {
+		t.Error("other client cert: Healthy = true, want false")
+	}
+
+	ep.ClientCert, ep.ClientKey = clientCert, ""
+	if result := c.Check(ep); result.Healthy || !errors.Is(result.Error, ErrClientCertPair) {
+		t.Errorf("cert without key: error = %v, want %v", result.Error, ErrClientCertPair)
+	}
+}
+
+// TestCheck_Proxy tests routing a check through an explicit proxy
+func TestCheck_Proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxied request carries the absolute target URL
+		if r.URL.Host != "backend.invalid" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	c := New()
+	ep := Endpoint{URL: "http://backend.invalid/health", Timeout: 5 * time.Second, ExpectedStatus: 200, Proxy: proxy.URL}
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("via proxy: Healthy = false, error = %v", result.Error)
+	}
+
+	ep.Proxy = "ftp://proxy.example.com"
+	if result := c.Check(ep); result.Healthy || result.Error == nil || !strings.Contains(result.Error.Error(), "invalid proxy URL") {
+		t.Errorf("bad proxy: error = %v, want invalid proxy URL", result.Error)
+	}
+
+	// NoProxy bypasses the proxy, which would answer 502 for this host
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer direct.Close()
+	ep = Endpoint{URL: direct.URL + "/health", Timeout: 5 * time.Second, ExpectedStatus: 200, Proxy: proxy.URL, NoProxy: true}
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("no proxy: Healthy = false, error = %v", result.Error)
+	}
+	ep.NoProxy = false
+	if result := c.Check(ep); result.Healthy {
+		t.Error("via proxy to an unknown host: Healthy = true, want false")
+	}
+}
+
+// startTestSOCKS5 runs a SOCKS5 proxy requiring user/pass that connects every request to target
+// Each requested destination is sent on the returned channel
+func startTestSOCKS5(t *testing.T, target string) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	requested := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 512)
+				// Greeting: version, methods; choose username/password
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{5, 2})
+				// Username/password sub-negotiation (RFC 1929)
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				user := make([]byte, buf[1])
+				_, _ = io.ReadFull(conn, user)
+				_, _ = io.ReadFull(conn, buf[:1])
+				pass := make([]byte, buf[0])
+				_, _ = io.ReadFull(conn, pass)
+				if string(user) != "tunnel" || string(pass) != "s3cret" {
+					_, _ = conn.Write([]byte{1, 1})
+					return
+				}
+				_, _ = conn.Write([]byte{1, 0})
+				// CONNECT request with a domain name destination
+				if _, err := io.ReadFull(conn, buf[:5]); err != nil || buf[3] != 3 {
+					return
+				}
+				host := make([]byte, buf[4])
+				_, _ = io.ReadFull(conn, host)
+				_, _ = io.ReadFull(conn, buf[:2])
+				requested <- fmt.Sprintf("%s:%d", host, int(buf[0])<<8|int(buf[1]))
+
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer upstream.Close()
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return ln.Addr().String(), requested
+}
+
+// TestCheck_SOCKS5Proxy tests tunneling a check through a SOCKS5 proxy that resolves the host
+func TestCheck_SOCKS5Proxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	proxyAddr, requested := startTestSOCKS5(t, server.Listener.Addr().String())
+
+	c := New()
+	ep := Endpoint{URL: "http://backend.internal/health", Timeout: 5 * time.Second, ExpectedStatus: 200}
+	for _, scheme := range []string{"socks5", "socks5h"} {
+		ep.Proxy = scheme + "://tunnel:s3cret@" + proxyAddr
+		if result := c.Check(ep); !result.Healthy {
+			t.Fatalf("%s: Healthy = false, error = %v", scheme, result.Error)
+		}
+		if got := <-requested; got != "backend.internal:80" {
+			t.Errorf("%s: proxy asked to connect to %q, want backend.internal:80", scheme, got)
+		}
+	}
+
+	ep.Proxy = "socks5://tunnel:wrong@" + proxyAddr
+	if result := c.Check(ep); result.Healthy {
+		t.Error("wrong proxy password: Healthy = true, want false")
+	}
+}
+
+// TestParseResolve tests parsing curl-style host:port:addr overrides
+func TestParseResolve(t *testing.T) {
+	tests := []struct {
+		entry    string
+		hostPort string
+		target   string
+		wantErr  bool
+	}{
+		{"api.example.com:443:10.0.3.12", "api.example.com:443", "10.0.3.12:443", false},
+		{"API.example.com:8080:127.0.0.1", "api.example.com:8080", "127.0.0.1:8080", false},
+		{"api.example.com:443:[2001:db8::1]", "api.example.com:443", "[2001:db8::1]:443", false},
+		{"api.example.com:443", "", "", true},
+		{"api.example.com:https:10.0.0.1", "", "", true},
+		{"api.example.com:443:backend.internal", "", "", true},
+		{":443:10.0.0.1", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			hostPort, target, err := ParseResolve(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hostPort != tt.hostPort || target != tt.target {
+				t.Errorf("ParseResolve() = %q, %q, want %q, %q", hostPort, target, tt.hostPort, tt.target)
+			}
+		})
+	}
+}
+
+// TestCheck_Resolve tests dialing an override address while keeping Host and SNI
+func TestCheck_Resolve(t *testing.T) {
+	var host, serverName atomic.Value
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.Store(r.Host)
+		serverName.Store(r.TLS.ServerName)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	// The test certificate is valid for example.com, so verification also covers SNI
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	c := New(WithDNSProbe(true))
+	ep := Endpoint{
+		URL:            "https://example.com:" + port + "/health",
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		CACert:         certFile,
+		Resolve:        []string{"example.com:" + port + ":127.0.0.1"},
+	}
+	result := c.Check(ep)
+	if !result.Healthy {
+		t.Fatalf("Healthy = false, error = %v", result.Error)
+	}
+	if got := host.Load(); got != "example.com:"+port {
+		t.Errorf("Host = %v, want example.com:%s", got, port)
+	}
+	if got := serverName.Load(); got != "example.com" {
+		t.Errorf("ServerName = %v, want example.com", got)
+	}
+	if !reflect.DeepEqual(result.DNSResolved, []string{"127.0.0.1"}) {
+		t.Errorf("DNSResolved = %v, want [127.0.0.1]", result.DNSResolved)
+	}
+}
+
+// TestCheck_TLSALPN tests offered ALPN protocols and the negotiated result
+func TestCheck_TLSALPN(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		alpn    []string
+		healthy bool
+		want    string
+	}{
+		{"h2 preferred", []string{"h2", "http/1.1"}, true, "h2"},
+		{"http1 only", []string{"http/1.1"}, true, "http/1.1"},
+		{"no overlap", []string{"acme-tls/1"}, false, ""},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				Name:           "test-server",
+				URL:            server.URL,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				Insecure:       true,
+				TLSALPN:        tt.alpn,
+			})
+			if result.Healthy != tt.healthy || result.ALPN != tt.want {
+				t.Errorf("Healthy = %v, ALPN = %q, want %v, %q (error: %v)", result.Healthy, result.ALPN, tt.healthy, tt.want, result.Error)
+			}
+			if !tt.healthy && result.ErrorKind != KindTLS {
+				t.Errorf("ErrorKind = %q, want %q", result.ErrorKind, KindTLS)
+			}
+		})
+	}
+}
+
+// TestCheck_ExpectedProto tests protocol version assertions
+func TestCheck_ExpectedProto(t *testing.T) {
+	h2 := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	h1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer h1.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		proto   string
+		healthy bool
+	}{
+		{"http2 expected and served", h2.URL, "HTTP/2.0", true},
+		{"http1 expected and served", h1.URL, "HTTP/1.1", true},
+		{"http2 expected, http1 served", h1.URL, "HTTP/2.0", false},
+	}
+
+	c := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(Endpoint{
+				URL:            tt.url,
+				Timeout:        5 * time.Second,
+				ExpectedStatus: 200,
+				Insecure:       true,
+				ExpectedProto:  tt.proto,
+			})
+			if result.Healthy != tt.healthy {
+				t.Fatalf("Healthy = %v, want %v (error: %v)", result.Healthy, tt.healthy, result.Error)
+			}
+			if !tt.healthy && result.ErrorKind != KindProto {
+				t.Errorf("ErrorKind = %q, want %q", result.ErrorKind, KindProto)
+			}
+		})
+	}
+}
+
+// TestCheck_TLSServerName tests that the configured SNI is sent in the TLS handshake
+func TestCheck_TLSServerName(t *testing.T) {
+	var gotSNI string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSNI = r.TLS.ServerName
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	result := c.Check(Endpoint{
+		Name:           "sni",
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Insecure:       true,
+		TLSServerName:  "tenant.example.com",
+	})
+
+	if !result.Healthy {
+		t.Fatalf("Healthy = false, error = %v", result.Error)
+	}
+	if gotSNI != "tenant.example.com" {
+		t.Errorf("ServerName = %q, want %q", gotSNI, "tenant.example.com")
+	}
+}
+
+// TestWithCertInfo tests recording the served certificate for HTTPS endpoints only
+func TestWithCertInfo(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+
+	ep := Endpoint{URL: tlsServer.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, Insecure: true}
+	if result := New().Check(ep); result.Cert != nil {
+		t.Errorf("Cert = %+v, want nil without WithCertInfo", result.Cert)
+	}
+
+	c := New(WithCertInfo(true))
+	result := c.Check(ep)
+	leaf := tlsServer.Certificate()
+	if result.Cert == nil {
+		t.Fatalf("Cert = nil, want certificate details (error: %v)", result.Error)
+	}
+	if result.Cert.Subject != leaf.Subject.String() || result.Cert.Issuer != leaf.Issuer.String() {
+		t.Errorf("Subject, Issuer = %q, %q, want %q, %q", result.Cert.Subject, result.Cert.Issuer, leaf.Subject, leaf.Issuer)
+	}
+	if !slices.Equal(result.Cert.DNSNames, leaf.DNSNames) {
+		t.Errorf("DNSNames = %v, want %v", result.Cert.DNSNames, leaf.DNSNames)
+	}
+	if !result.Cert.NotAfter.Equal(leaf.NotAfter) {
+		t.Errorf("NotAfter = %v, want %v", result.Cert.NotAfter, leaf.NotAfter)
+	}
+
+	// Plain HTTP has no certificate, and that's not an error
+	result = c.Check(Endpoint{URL: plainServer.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if !result.Healthy || result.Cert != nil {
+		t.Errorf("http: Healthy = %v, Cert = %+v, want healthy with nil Cert", result.Healthy, result.Cert)
+	}
+}
+
+// TestCheck_AddCACert tests trusting a private CA on top of the system pool
+func TestCheck_AddCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, certPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	c := New()
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+
+	if result := c.Check(ep); result.Healthy || result.ErrorKind != KindTLS {
+		t.Errorf("without CA: Healthy = %v, ErrorKind = %q, want unhealthy tls", result.Healthy, result.ErrorKind)
+	}
+
+	ep.AddCACert = caPath
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("with CA: Healthy = false, error = %v", result.Error)
+	}
+
+	// Unreadable or empty CA files are reported as check failures, not TLS failures
+	ep.AddCACert = filepath.Join(t.TempDir(), "missing.pem")
+	if result := c.Check(ep); result.Healthy || result.ErrorKind != KindOther {
+		t.Errorf("missing CA: Healthy = %v, ErrorKind = %q, want unhealthy other", result.Healthy, result.ErrorKind)
+	}
+}
+
+// TestCheck_CACert tests trusting only a custom CA bundle instead of the system pool
+func TestCheck_CACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writePEM := func(name string, cert *x509.Certificate) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return path
+	}
+	serverCA := writePEM("server.pem", server.Certificate())
+	otherCert, _ := newTestCert(t, "Other CA", true, nil, nil)
+	otherCA := writePEM("other.pem", otherCert)
+
+	// One checker, so differing bundles must not share a cached client
+	c := New()
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+
+	ep.CACert = serverCA
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("server CA: Healthy = false, error = %v", result.Error)
+	}
+
+	ep.CACert = otherCA
+	if result := c.Check(ep); result.Healthy || result.ErrorKind != KindTLS {
+		t.Errorf("other CA: Healthy = %v, ErrorKind = %q, want unhealthy tls", result.Healthy, result.ErrorKind)
+	}
+
+	// An extra CA extends the custom bundle
+	ep.AddCACert = serverCA
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("other CA plus extra CA: Healthy = false, error = %v", result.Error)
+	}
+}
+
+// TestLoadCertPool_NoCertificates tests rejecting files without PEM certificates
+func TestLoadCertPool_NoCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := LoadCertPool(path); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("LoadCertPool() error = %v, want no PEM certificates error", err)
+	}
+}
+
+// newTestCert creates a certificate signed by parent, or self-signed when parent is nil
+func newTestCert(t *testing.T, name string, ca bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  ca,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return cert, key
+}
+
+// TestCheckCompleteChain tests detecting intermediates missing from the served chain
+func TestCheckCompleteChain(t *testing.T) {
+	root, rootKey := newTestCert(t, "Test Root", true, nil, nil)
+	inter, interKey := newTestCert(t, "Test Intermediate", true, root, rootKey)
+	leaf, leafKey := newTestCert(t, "127.0.0.1", false, inter, interKey)
+	selfSigned, _ := newTestCert(t, "self-signed", false, nil, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	rootsWithInter := x509.NewCertPool()
+	rootsWithInter.AddCert(root)
+	rootsWithInter.AddCert(inter)
+
+	tests := []struct {
+		name    string
+		peers   []*x509.Certificate
+		roots   *x509.CertPool
+		wantErr string
+	}{
+		{"complete", []*x509.Certificate{leaf, inter}, roots, ""},
+		{"complete with root", []*x509.Certificate{leaf, inter, root}, roots, ""},
+		{"missing intermediate", []*x509.Certificate{leaf}, roots, `incomplete certificate chain: issuer "Test Intermediate" of "127.0.0.1" not sent by server`},
+		{"intermediate only in trust store", []*x509.Certificate{leaf}, rootsWithInter, `incomplete certificate chain: intermediate "Test Intermediate" not sent by server`},
+		{"untrusted self-signed", []*x509.Certificate{selfSigned}, roots, "certificate chain does not verify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCompleteChain(tt.peers, tt.roots)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkCompleteChain() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkCompleteChain() error = %v, want %q", err, tt.wantErr)
+			}
+		})
 	}
 
-	batch := c.CheckAll(endpoints)
+	// The assertion runs against the chain the server actually sent
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{leaf.Raw}, PrivateKey: leafKey}}}
+	server.StartTLS()
+	defer server.Close()
 
-	// Verify result count
-	if len(batch.Results) != 3 {
-		t.Errorf("len(Results) = %d, want 3", len(batch.Results))
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	var caPEM []byte
+	for _, cert := range []*x509.Certificate{root, inter} {
+		caPEM = append(caPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
 	}
-
-	// Verify result order preserved
-	if batch.Results[0].Name != "server1" {
-		t.Errorf("Results[0].Name = %q, want %q", batch.Results[0].Name, "server1")
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
 	}
-	if batch.Results[1].Name != "server2" {
-		t.Errorf("Results[1].Name = %q, want %q", batch.Results[1].Name, "server2")
+
+	c := New()
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, AddCACert: caPath}
+	if result := c.Check(ep); !result.Healthy {
+		t.Errorf("without CompleteChain: Healthy = false, error = %v", result.Error)
 	}
-	if batch.Results[2].Name != "server3" {
-		t.Errorf("Results[2].Name = %q, want %q", batch.Results[2].Name, "server3")
+	ep.CompleteChain = true
+	if result := c.Check(ep); result.Healthy || result.ErrorKind != KindTLS {
+		t.Errorf("with CompleteChain: Healthy = %v, ErrorKind = %q, want unhealthy tls", result.Healthy, result.ErrorKind)
 	}
+}
 
-	// Verify summary
-	if batch.Summary.Total != 3 {
-		t.Errorf("Summary.Total = %d, want 3", batch.Summary.Total)
+// TestCheck_ConnectOnly tests that connect-only checks ignore the HTTP response
+func TestCheck_ConnectOnly(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond in time; only the connection matters
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := New()
+	result := c.Check(Endpoint{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		ConnectOnly:    true,
+	})
+	if !result.Healthy {
+		t.Fatalf("Healthy = false, error = %v", result.Error)
 	}
-	if batch.Summary.Healthy != 2 {
-		t.Errorf("Summary.Healthy = %d, want 2", batch.Summary.Healthy)
+	if result.StatusCode != nil {
+		t.Errorf("StatusCode = %d, want nil", *result.StatusCode)
 	}
-	if batch.Summary.Unhealthy != 1 {
-		t.Errorf("Summary.Unhealthy = %d, want 1", batch.Summary.Unhealthy)
+	if result.Latency >= time.Second {
+		t.Errorf("Latency = %v, want connection time only", result.Latency)
+	}
+
+	// Connection failures are still unhealthy
+	result = c.Check(Endpoint{URL: "http://127.0.0.1:1", Timeout: 2 * time.Second, ConnectOnly: true})
+	if result.Healthy || result.ErrorKind != KindRefused {
+		t.Errorf("Healthy = %v, ErrorKind = %q, want unhealthy refused", result.Healthy, result.ErrorKind)
 	}
 }
 
-// TestCheckAll_EmptyEndpoints tests empty endpoint list
-func TestCheckAll_EmptyEndpoints(t *testing.T) {
-	c := New()
-	batch := c.CheckAll([]Endpoint{})
+// TestFilterByTags tests selecting endpoints by tag
+func TestFilterByTags(t *testing.T) {
+	endpoints := []Endpoint{
+		{Name: "api", Tags: []string{"critical", "api"}},
+		{Name: "web", Tags: []string{"web"}},
+		{Name: "untagged"},
+	}
 
-	if len(batch.Results) != 0 {
-		t.Errorf("len(Results) = %d, want 0", len(batch.Results))
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{"no filter", nil, []string{"api", "web", "untagged"}},
+		{"single tag", []string{"critical"}, []string{"api"}},
+		{"any of tags", []string{"web", "api"}, []string{"api", "web"}},
+		{"unknown tag", []string{"typo"}, []string{}},
 	}
-	if batch.Summary.Total != 0 {
-		t.Errorf("Summary.Total = %d, want 0", batch.Summary.Total)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterByTags(endpoints, tt.tags)
+			names := make([]string, 0, len(filtered))
+			for _, ep := range filtered {
+				names = append(names, ep.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FilterByTags(%v) = %v, want %v", tt.tags, names, tt.expected)
+			}
+		})
 	}
 }
 
-// TestCategorizeError tests error categorization
-func TestCategorizeError(t *testing.T) {
-	c := New()
+// TestFilterByAllTags tests selecting endpoints that carry every tag
+func TestFilterByAllTags(t *testing.T) {
+	endpoints := []Endpoint{
+		{Name: "prod-critical", Tags: []string{"prod", "critical"}},
+		{Name: "prod", Tags: []string{"prod"}},
+		{Name: "staging-critical", Tags: []string{"staging", "critical"}},
+		{Name: "untagged"},
+	}
 
 	tests := []struct {
 		name     string
-		err      error
-		contains string
+		tags     []string
+		expected []string
 	}{
-		{"DNS error", errors.New("no such host"), "DNS resolution failed"},
-		{"Connection refused", errors.New("connection refused"), "connection refused"},
-		{"Context deadline", errors.New("context deadline exceeded"), "connection timeout"},
-		{"Timeout", errors.New("request timeout"), "timeout"},
-		{"Certificate error", errors.New("certificate verify failed"), "SSL certificate error"},
-		{"Unknown error", errors.New("some random error"), "some random error"},
+		{"no filter", nil, []string{"prod-critical", "prod", "staging-critical", "untagged"}},
+		{"single tag", []string{"critical"}, []string{"prod-critical", "staging-critical"}},
+		{"all of tags", []string{"prod", "critical"}, []string{"prod-critical"}},
+		{"no endpoint has all", []string{"prod", "staging"}, []string{}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := c.categorizeError(tt.err)
-			if !strings.Contains(result.Error(), tt.contains) {
-				t.Errorf("categorizeError(%q) = %q, want to contain %q", tt.err, result.Error(), tt.contains)
+			filtered := FilterByAllTags(endpoints, tt.tags)
+			names := make([]string, 0, len(filtered))
+			for _, ep := range filtered {
+				names = append(names, ep.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("FilterByAllTags(%v) = %v, want %v", tt.tags, names, tt.expected)
 			}
 		})
 	}
 }
 
-// TestCalculateSummary tests summary calculation
-func TestCalculateSummary(t *testing.T) {
-	c := New()
-
+// TestSortResults tests ordering results worst first
+func TestSortResults(t *testing.T) {
+	ok := 200
+	bad := 500
 	results := []Result{
-		{Healthy: true},
-		{Healthy: false},
-		{Healthy: true},
-		{Healthy: true},
-		{Healthy: false},
+		{Name: "fast-ok", Healthy: true, StatusCode: &ok, Latency: 10 * time.Millisecond},
+		{Name: "slow-bad", StatusCode: &bad, Latency: 300 * time.Millisecond},
+		{Name: "refused", Latency: 1 * time.Millisecond},
+		{Name: "slow-ok", Healthy: true, StatusCode: &ok, Latency: 500 * time.Millisecond},
+		{Name: "fast-bad", StatusCode: &bad, Latency: 20 * time.Millisecond},
 	}
 
-	summary := c.calculateSummary(results, 100*time.Millisecond)
+	tests := []struct {
+		key      SortKey
+		expected []string
+	}{
+		{SortByLatency, []string{"slow-ok", "slow-bad", "fast-bad", "fast-ok", "refused"}},
+		{SortByFailure, []string{"refused", "slow-bad", "fast-bad", "slow-ok", "fast-ok"}},
+	}
 
-	if summary.Total != 5 {
-		t.Errorf("Total = %d, want 5", summary.Total)
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			sorted := append([]Result(nil), results...)
+			if err := SortResults(sorted, tt.key); err != nil {
+				t.Fatalf("SortResults() error = %v", err)
+			}
+			names := make([]string, len(sorted))
+			for i, r := range sorted {
+				names[i] = r.Name
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("SortResults(%s) = %v, want %v", tt.key, names, tt.expected)
+			}
+		})
 	}
-	if summary.Healthy != 3 {
-		t.Errorf("Healthy = %d, want 3", summary.Healthy)
+
+	if err := SortResults(results, "name"); err == nil {
+		t.Error("SortResults(unknown) error = nil, want error")
 	}
-	if summary.Unhealthy != 2 {
-		t.Errorf("Unhealthy = %d, want 2", summary.Unhealthy)
+}
+
+// TestParseServerTiming tests Server-Timing header parsing
+func TestParseServerTiming(t *testing.T) {
+	header := http.Header{}
+	header.Add("Server-Timing", `db;dur=53, app;dur=47.2;desc="App, main"`)
+	header.Add("Server-Timing", "cache;desc=hit, miss")
+
+	metrics := parseServerTiming(header)
+
+	expected := []ServerTimingMetric{
+		{Name: "db", Duration: 53 * time.Millisecond},
+		{Name: "app", Duration: 47200 * time.Microsecond, Description: "App, main"},
+		{Name: "cache", Description: "hit"},
+		{Name: "miss"},
 	}
-	if summary.Duration != 100*time.Millisecond {
-		t.Errorf("Duration = %v, want %v", summary.Duration, 100*time.Millisecond)
+
+	if len(metrics) != len(expected) {
+		t.Fatalf("len(metrics) = %d, want %d: %+v", len(metrics), len(expected), metrics)
+	}
+	for i, want := range expected {
+		if metrics[i] != want {
+			t.Errorf("metrics[%d] = %+v, want %+v", i, metrics[i], want)
+		}
+	}
+
+	if got := parseServerTiming(http.Header{}); got != nil {
+		t.Errorf("parseServerTiming(empty) = %v, want nil", got)
 	}
 }
 
-// TestCalculateSummary_Empty tests empty result summary
-func TestCalculateSummary_Empty(t *testing.T) {
-	c := New()
+// TestCheck_ServerTiming tests recording Server-Timing from a response
+func TestCheck_ServerTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server-Timing", "total;dur=12")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
 
-	summary := c.calculateSummary([]Result{}, 0)
+	result := New().Check(Endpoint{Name: "timed", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
 
-	if summary.Total != 0 {
-		t.Errorf("Total = %d, want 0", summary.Total)
+	if len(result.ServerTiming) != 1 || result.ServerTiming[0].Duration != 12*time.Millisecond {
+		t.Errorf("ServerTiming = %+v, want total 12ms", result.ServerTiming)
 	}
-	if summary.Healthy != 0 {
-		t.Errorf("Healthy = %d, want 0", summary.Healthy)
+}
+
+// TestCheck_Timings tests the connection phase breakdown for new and reused connections
+func TestCheck_Timings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, Insecure: true}
+
+	first := c.Check(ep).Timings
+	if first == nil || first.ConnReused || first.Connect <= 0 || first.TLS <= 0 || first.TTFB < 10*time.Millisecond {
+		t.Fatalf("first Timings = %+v, want new connection with connect, TLS and TTFB of at least 10ms", first)
 	}
-	if summary.Unhealthy != 0 {
-		t.Errorf("Unhealthy = %d, want 0", summary.Unhealthy)
+
+	second := c.Check(ep).Timings
+	if second == nil || !second.ConnReused || second.Connect != 0 || second.TLS != 0 || second.TTFB <= 0 {
+		t.Errorf("second Timings = %+v, want reused connection with only TTFB", second)
+	}
+
+	// No connection attempt, no timings
+	if result := c.Check(Endpoint{URL: "http://[::1", Timeout: time.Second}); result.Timings != nil {
+		t.Errorf("invalid URL Timings = %+v, want nil", result.Timings)
 	}
 }
 
-// TestCheck_InvalidURL tests invalid URL
-func TestCheck_InvalidURL(t *testing.T) {
-	c := New()
+// TestWithRequestDump tests dumping outgoing requests with secrets masked
+func TestWithRequestDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
 	ep := Endpoint{
-		Name:           "invalid",
-		URL:            "not-a-valid-url",
-		Timeout:        1 * time.Second,
+		Name:           "dump",
+		URL:            server.URL + "/health?x=1&api_key=k3y",
+		Timeout:        5 * time.Second,
 		ExpectedStatus: 200,
+		Headers: map[string]string{
+			"Authorization": "Bearer secret123",
+			"X-Api-Key":     "abc",
+			"X-Request-ID":  "req-1",
+		},
 	}
 
-	result := c.Check(ep)
-
-	if result.Healthy {
-		t.Error("Healthy = true, want false")
+	tests := []struct {
+		name     string
+		unmask   bool
+		contains []string
+		excludes []string
+	}{
+		{"masked", false,
+			[]string{"> GET /health?x=1&api_key=*** HTTP/1.1\n", "> Host: " + strings.TrimPrefix(server.URL, "http://"), "> Authorization: Bearer ****\n", "> X-Api-Key: ****\n", "> X-Request-Id: req-1\n", "> User-Agent: healthcheck-cli/"},
+			[]string{"secret123", "abc", "k3y"}},
+		{"unmasked", true,
+			[]string{"> GET /health?x=1&api_key=k3y HTTP/1.1\n", "> Authorization: Bearer secret123\n", "> X-Api-Key: abc\n"},
+			nil},
 	}
-	if result.Error == nil {
-		t.Error("Error = nil, want error")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			c := New(WithRequestDump(&buf, tt.unmask))
+
+			if result := c.Check(ep); !result.Healthy {
+				t.Fatalf("Healthy = false, error: %v", result.Error)
+			}
+
+			dump := buf.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(dump, want) {
+					t.Errorf("dump should contain %q, got:\n%s", want, dump)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(dump, unwanted) {
+					t.Errorf("dump should not contain %q, got:\n%s", unwanted, dump)
+				}
+			}
+		})
 	}
 }
 
-// TestCheckWithContext tests check with context
-func TestCheckWithContext(t *testing.T) {
+// TestWithDebug tests recording request and response headers with secrets masked
+func TestWithDebug(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		w.Header().Set("X-Backend", "api-2")
+		w.WriteHeader(http.StatusServiceUnavailable)
 	}))
 	defer server.Close()
 
-	c := New()
-	ctx := context.Background()
 	ep := Endpoint{
-		Name:           "test-server",
-		URL:            server.URL,
+		Name:           "debug",
+		URL:            server.URL + "/health?x=1",
 		Timeout:        5 * time.Second,
 		ExpectedStatus: 200,
+		Headers:        map[string]string{"Authorization": "Bearer secret123"},
 	}
 
-	result := c.CheckWithContext(ctx, ep)
+	if result := New().Check(ep); result.Debug != nil {
+		t.Errorf("Debug = %+v, want nil without WithDebug", result.Debug)
+	}
 
-	if !result.Healthy {
-		t.Error("Healthy = false, want true")
+	result := New(WithDebug(true, false)).Check(ep)
+	d := result.Debug
+	if d == nil {
+		t.Fatal("Debug = nil, want request and response detail")
+	}
+	if d.Request != "GET /health?x=1 HTTP/1.1" {
+		t.Errorf("Request = %q, want %q", d.Request, "GET /health?x=1 HTTP/1.1")
+	}
+	if got := d.RequestHeader.Get("Authorization"); got != "Bearer ****" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer ****")
+	}
+	if got, want := d.RequestHeader.Get("Host"), strings.TrimPrefix(server.URL, "http://"); got != want {
+		t.Errorf("Host = %q, want %q", got, want)
+	}
+	if d.Status != "HTTP/1.1 503 Service Unavailable" {
+		t.Errorf("Status = %q, want %q", d.Status, "HTTP/1.1 503 Service Unavailable")
+	}
+	if got := d.ResponseHeader.Get("Set-Cookie"); got != "****" {
+		t.Errorf("Set-Cookie = %q, want %q", got, "****")
+	}
+	if got := d.ResponseHeader.Get("X-Backend"); got != "api-2" {
+		t.Errorf("X-Backend = %q, want %q", got, "api-2")
+	}
+	if ep.Headers["Authorization"] != "Bearer secret123" {
+		t.Errorf("endpoint headers modified: %v", ep.Headers)
+	}
+
+	result = New(WithDebug(true, true)).Check(ep)
+	if got := result.Debug.RequestHeader.Get("Authorization"); got != "Bearer secret123" {
+		t.Errorf("unmasked Authorization = %q, want %q", got, "Bearer secret123")
+	}
+
+	// Connection failures keep the request but have no response
+	result = New(WithDebug(true, false)).Check(Endpoint{URL: "http://127.0.0.1:1", Timeout: time.Second})
+	if result.Debug == nil || result.Debug.Request == "" || result.Debug.Status != "" {
+		t.Errorf("connection failure Debug = %+v, want request only", result.Debug)
+	}
+
+	// Secret query parameters are masked in the request line; Host is what the request carries
+	req, err := http.NewRequest(http.MethodGet, "http://10.0.0.1/health?api_key=k3y&x=1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Host = "api.example.com"
+	d = New(WithDebug(true, false)).debugRequest(req)
+	if d.Request != "GET /health?api_key=***&x=1 HTTP/1.1" || d.RequestHeader.Get("Host") != "api.example.com" {
+		t.Errorf("debugRequest() = %q, Host %q, want masked api_key and Host api.example.com", d.Request, d.RequestHeader.Get("Host"))
+	}
+	if d = New(WithDebug(true, true)).debugRequest(req); !strings.Contains(d.Request, "api_key=k3y") {
+		t.Errorf("unmasked debugRequest() = %q, want api_key=k3y", d.Request)
 	}
 }
 
-// TestCheckWithContext_Cancelled tests context cancellation
-func TestCheckWithContext_Cancelled(t *testing.T) {
+// TestConnStats tests connection reuse tracking
+func TestConnStats(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(200 * time.Millisecond)
 		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("OK")) // Unread body must not prevent reuse
 	}))
 	defer server.Close()
 
 	c := New()
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // Cancel immediately
+	ep := Endpoint{Name: "pooled", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
 
-	ep := Endpoint{
-		Name:           "test-server",
-		URL:            server.URL,
-		Timeout:        5 * time.Second,
-		ExpectedStatus: 200,
+	for i := 0; i < 3; i++ {
+		if result := c.Check(ep); !result.Healthy {
+			t.Fatalf("check %d: Healthy = false, error: %v", i, result.Error)
+		}
 	}
 
-	result := c.CheckWithContext(ctx, ep)
-
-	if result.Healthy {
-		t.Error("Healthy = true, want false (cancelled)")
+	stats := c.ConnStats()
+	if stats.New != 1 {
+		t.Errorf("New = %d, want 1", stats.New)
 	}
-	if result.Error == nil {
-		t.Error("Error = nil, want context cancelled error")
+	if stats.Reused != 2 {
+		t.Errorf("Reused = %d, want 2", stats.Reused)
 	}
 }
 
-// TestCheckAllWithContext tests batch check with context
-func TestCheckAllWithContext(t *testing.T) {
+// BenchmarkCheck measures time and allocations of a single check against a local server
+func BenchmarkCheck(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
 
 	c := New()
-	ctx := context.Background()
-	endpoints := []Endpoint{
-		{Name: "server1", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200},
+	ep := Endpoint{Name: "bench", URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if result := c.Check(ep); !result.Healthy {
+			b.Fatalf("Healthy = false (error: %v)", result.Error)
+		}
 	}
+}
 
-	batch := c.CheckAllWithContext(ctx, endpoints)
+// BenchmarkCheckAll measures a 100-endpoint batch; divide by 100 for the cost per check
+func BenchmarkCheckAll(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
 
-	if len(batch.Results) != 1 {
-		t.Errorf("len(Results) = %d, want 1", len(batch.Results))
+	endpoints := make([]Endpoint, 100)
+	for i := range endpoints {
+		endpoints[i] = Endpoint{Name: fmt.Sprintf("ep-%d", i), URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
 	}
-	if !batch.Results[0].Healthy {
-		t.Error("Results[0].Healthy = false, want true")
+
+	c := New(WithConcurrency(10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if batch := c.CheckAll(endpoints); batch.Summary.Unhealthy > 0 {
+			b.Fatalf("Summary.Unhealthy = %d, want 0", batch.Summary.Unhealthy)
+		}
 	}
 }
 
-// TestCategorizeError_ContextCanceled tests context canceled error categorization
-func TestCategorizeError_ContextCanceled(t *testing.T) {
-	c := New()
-	err := errors.New("context canceled")
-	result := c.categorizeError(err)
+// newTestOCSPResponse builds an OCSP response for serial of issuer, signed by key
+// as responder (embedded unless it is the issuer); status is good, revoked or unknown
+func newTestOCSPResponse(t *testing.T, serial *big.Int, issuer, responder *x509.Certificate, key *ecdsa.PrivateKey, status OCSPStatus, nextUpdate time.Time) []byte {
+	t.Helper()
+	template := ocsp.Response{
+		SerialNumber: serial,
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   nextUpdate,
+	}
+	switch status {
+	case OCSPGood:
+		template.Status = ocsp.Good
+	case OCSPUnknown:
+		template.Status = ocsp.Unknown
+	case OCSPRevoked:
+		template.Status = ocsp.Revoked
+		template.RevokedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	if responder != issuer {
+		template.Certificate = responder
+	}
+	der, err := ocsp.CreateResponse(issuer, responder, template, key)
+	if err != nil {
+		t.Fatalf("CreateResponse() error = %v", err)
+	}
+	return der
+}
 
-	if !strings.Contains(result.Error(), "request canceled") {
-		t.Errorf("categorizeError() = %q, want to contain 'request canceled'", result.Error())
+// newTestOCSPCertID returns the CertID a request for serial of issuer carries
+func newTestOCSPCertID(t *testing.T, serial *big.Int, issuer *x509.Certificate) *ocsp.Request {
+	t.Helper()
+	der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: serial}, issuer, nil)
+	if err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	certID, err := ocsp.ParseRequest(der)
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
 	}
+	return certID
 }
 
-// TestGetClientKey tests client cache key generation
-func TestGetClientKey(t *testing.T) {
+// TestParseOCSPResponse tests status extraction and response verification
+func TestParseOCSPResponse(t *testing.T) {
+	issuer, issuerKey := newTestCert(t, "Test CA", true, nil, nil)
+	other, otherKey := newTestCert(t, "Other CA", true, nil, nil)
+	responder, responderKey := newTestCert(t, "Test Responder", false, issuer, issuerKey)
+	serial := big.NewInt(4242)
+	later := time.Now().Add(time.Hour)
+
 	tests := []struct {
-		insecure        bool
-		followRedirects bool
-		expected        string
+		name    string
+		resp    []byte
+		serial  *big.Int
+		want    OCSPStatus
+		wantErr string
 	}{
-		{false, true, "secure-follow"},
-		{false, false, "secure-nofollow"},
-		{true, true, "insecure-follow"},
-		{true, false, "insecure-nofollow"},
+		{"good", newTestOCSPResponse(t, serial, issuer, issuer, issuerKey, OCSPGood, later), serial, OCSPGood, ""},
+		{"revoked", newTestOCSPResponse(t, serial, issuer, issuer, issuerKey, OCSPRevoked, later), serial, OCSPRevoked, ""},
+		{"unknown", newTestOCSPResponse(t, serial, issuer, issuer, issuerKey, OCSPUnknown, later), serial, OCSPUnknown, ""},
+		{"wrong signer", newTestOCSPResponse(t, serial, issuer, issuer, otherKey, OCSPGood, later), serial, "", "bad OCSP signature"},
+		{"expired", newTestOCSPResponse(t, serial, issuer, issuer, issuerKey, OCSPGood, time.Now().Add(-time.Minute)), serial, "", "OCSP response expired"},
+		{"other serial", newTestOCSPResponse(t, serial, issuer, issuer, issuerKey, OCSPGood, later), big.NewInt(1), "", "no response matching"},
+		// Signed by the issuer, but about the same serial number of another CA
+		{"other issuer", newTestOCSPResponse(t, serial, other, issuer, issuerKey, OCSPGood, later), serial, "", "another issuer"},
+		{"unauthorized responder", newTestOCSPResponse(t, serial, issuer, responder, responderKey, OCSPGood, later), serial, "", "not authorized for OCSP signing"},
+		{"garbage", []byte("not ocsp"), serial, "", "invalid OCSP response"},
+		{"error status", ocsp.UnauthorizedErrorResponse, serial, "", "error status: unauthorized"},
 	}
 
 	for _, tt := range tests {
-		result := getClientKey(tt.insecure, tt.followRedirects)
-		if result != tt.expected {
-			t.Errorf("getClientKey(%v, %v) = %q, want %q", tt.insecure, tt.followRedirects, result, tt.expected)
+		t.Run(tt.name, func(t *testing.T) {
+			got, revokedAt, err := parseOCSPResponse(tt.resp, newTestOCSPCertID(t, tt.serial, issuer), issuer, time.Now())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseOCSPResponse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOCSPResponse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("status = %q, want %q", got, tt.want)
+			}
+			if tt.want == OCSPRevoked && !revokedAt.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+				t.Errorf("revokedAt = %v, want 2026-01-02T03:04:05Z", revokedAt)
+			}
+		})
+	}
+}
+
+// TestCheck_OCSP tests revoked certificates fail and unknown ones only warn
+func TestCheck_OCSP(t *testing.T) {
+	ca, caKey := newTestCert(t, "Test CA", true, nil, nil)
+
+	var status atomic.Value
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
 		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(newTestOCSPResponse(t, req.SerialNumber, ca, ca, caKey, status.Load().(OCSPStatus), time.Now().Add(time.Hour)))
+	}))
+	defer responder.Close()
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(77),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		OCSPServer:   []string{responder.URL},
+	}, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{leafDER, ca.Raw}, PrivateKey: leafKey}}}
+	server.StartTLS()
+	defer server.Close()
+
+	c := New(WithOCSP(true))
+	ep := Endpoint{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200, Insecure: true}
+
+	status.Store(OCSPGood)
+	if result := c.Check(ep); !result.Healthy || result.OCSP != OCSPGood || len(result.Warnings) > 0 {
+		t.Errorf("good: Healthy = %v, OCSP = %q, warnings = %v, error = %v", result.Healthy, result.OCSP, result.Warnings, result.Error)
+	}
+
+	status.Store(OCSPUnknown)
+	if result := c.Check(ep); !result.Healthy || len(result.Warnings) != 1 {
+		t.Errorf("unknown: Healthy = %v, warnings = %v, want healthy with a warning", result.Healthy, result.Warnings)
+	}
+
+	status.Store(OCSPRevoked)
+	result := c.Check(ep)
+	if result.Healthy || result.ErrorKind != KindTLS || !strings.Contains(fmt.Sprint(result.Error), "certificate revoked") {
+		t.Errorf("revoked: Healthy = %v, error = %v (%s), want revoked failure", result.Healthy, result.Error, result.ErrorKind)
+	}
+
+	// Without the option the responder is not queried
+	if result := New().Check(ep); !result.Healthy || result.OCSP != "" {
+		t.Errorf("disabled: Healthy = %v, OCSP = %q", result.Healthy, result.OCSP)
 	}
 }
@@ -0,0 +1,45 @@
+// Client certificates
+// Loads the TLS client identity presented for mutual TLS
+package checker
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+)
+
+// ErrClientCertPair is returned when only one of the client certificate and key is set
+var ErrClientCertPair = errors.New("client certificate and key must be set together")
+
+// LoadClientCert loads a PEM client certificate and its private key
+// Either path being empty is an error rather than a silent fallback to no client certificate
+func LoadClientCert(certFile, keyFile string) (tls.Certificate, error) {
+	if certFile == "" || keyFile == "" {
+		return tls.Certificate{}, ErrClientCertPair
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// clientCertificates returns the certificates presented in the TLS handshake, or nil for none
+func (ep Endpoint) clientCertificates() ([]tls.Certificate, error) {
+	switch {
+	case ep.ClientCertP12 != "":
+		cert, err := LoadPKCS12(ep.ClientCertP12, ep.ClientCertP12Pass)
+		if err != nil {
+			return nil, err
+		}
+		return []tls.Certificate{cert}, nil
+	case ep.ClientCert != "" || ep.ClientKey != "":
+		cert, err := LoadClientCert(ep.ClientCert, ep.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		return []tls.Certificate{cert}, nil
+	default:
+		return nil, nil
+	}
+}
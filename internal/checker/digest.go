@@ -0,0 +1,185 @@
+// HTTP authentication
+// Implements Basic and Digest (RFC 7616) request authentication
+package checker
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Supported authentication types
+const (
+	AuthBasic  = "basic"
+	AuthDigest = "digest"
+	AuthAWSv4  = "awsv4"
+)
+
+// digestChallenge holds the parameters of a WWW-Authenticate Digest challenge
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// parseDigestChallenge finds and parses the first Digest challenge in WWW-Authenticate values
+func parseDigestChallenge(values []string) (digestChallenge, bool) {
+	for _, v := range values {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+
+		p := parseAuthParams(params)
+		return digestChallenge{
+			realm:     p["realm"],
+			nonce:     p["nonce"],
+			opaque:    p["opaque"],
+			algorithm: p["algorithm"],
+			qop:       p["qop"],
+		}, true
+	}
+	return digestChallenge{}, false
+}
+
+// parseAuthParams parses comma-separated key=value pairs, values optionally quoted
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s != "" {
+		s = strings.TrimLeft(s, " ,")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimSpace(rest)
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			// Quoted string, may contain commas and escaped characters
+			var b strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				b.WriteByte(rest[i])
+			}
+			value = b.String()
+			s = rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// authorization computes the Authorization header value answering the challenge
+func (ch digestChallenge) authorization(username, password, method, uri, cnonce string) (string, error) {
+	algorithm := strings.ToUpper(ch.algorithm)
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "MD5":
+		newHash = md5.New // #nosec G401 - required by the Digest scheme
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm '%s'", ch.algorithm)
+	}
+	h := func(s string) string {
+		sum := newHash()
+		sum.Write([]byte(s))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	// Only the "auth" quality of protection is supported; omitted qop is the RFC 2069 legacy form
+	var qop string
+	if ch.qop != "" {
+		for _, q := range strings.Split(ch.qop, ",") {
+			if strings.TrimSpace(q) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return "", fmt.Errorf("unsupported digest qop '%s'", ch.qop)
+		}
+	}
+
+	const nc = "00000001"
+	ha1 := h(username + ":" + ch.realm + ":" + password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1 + ":" + ch.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	var response string
+	if qop == "" {
+		response = h(ha1 + ":" + ch.nonce + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + ch.nonce + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf(`username="%s"`, username),
+		fmt.Sprintf(`realm="%s"`, ch.realm),
+		fmt.Sprintf(`nonce="%s"`, ch.nonce),
+		fmt.Sprintf(`uri="%s"`, uri),
+		fmt.Sprintf(`response="%s"`, response),
+	}
+	if ch.algorithm != "" {
+		fields = append(fields, "algorithm="+ch.algorithm)
+	}
+	if qop != "" {
+		fields = append(fields, "qop="+qop, "nc="+nc, fmt.Sprintf(`cnonce="%s"`, cnonce))
+	}
+	if ch.opaque != "" {
+		fields = append(fields, fmt.Sprintf(`opaque="%s"`, ch.opaque))
+	}
+	return "Digest " + strings.Join(fields, ", "), nil
+}
+
+// answerDigest repeats a request that got a 401 Digest challenge with computed credentials
+// Responses without a Digest challenge are returned unchanged for the status check to report
+func (c *Checker) answerDigest(ctx context.Context, client *http.Client, ep Endpoint, resp *http.Response) (*http.Response, error) {
+	challenge, ok := parseDigestChallenge(resp.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MaxBodySize))
+	resp.Body.Close()
+
+	req, err := c.newRequest(ctx, ep)
+	if err != nil {
+		return nil, err
+	}
+
+	cnonce := make([]byte, 8)
+	if _, err := rand.Read(cnonce); err != nil {
+		return nil, fmt.Errorf("failed to generate digest cnonce: %w", err)
+	}
+	auth, err := challenge.authorization(ep.Username, ep.Password, req.Method, req.URL.RequestURI(), hex.EncodeToString(cnonce))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", auth)
+
+	if c.dumpWriter != nil {
+		c.dumpRequest(req)
+	}
+	return client.Do(req)
+}
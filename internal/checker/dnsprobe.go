@@ -0,0 +1,46 @@
+// DNS pre-check
+// Resolves the endpoint host before the HTTP request to report DNS separately
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// WithDNSProbe resolves each endpoint's host before the HTTP request
+// Resolution failures fail the check early as KindDNS and the resolved IPs are recorded
+func WithDNSProbe(enabled bool) Option {
+	return func(c *Checker) {
+		c.probeDNS = enabled
+	}
+}
+
+// probeDNS resolves the endpoint host, recording the addresses and lookup time on the result
+// IP literal hosts and hosts with a Resolve override need no lookup and are recorded as-is
+func probeDNS(ctx context.Context, ep Endpoint, result *Result) error {
+	u, err := url.Parse(ep.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	host := u.Hostname()
+	if net.ParseIP(host) != nil {
+		result.DNSResolved = []string{host}
+		return nil
+	}
+	if addr, ok := ep.resolvedAddr(u); ok {
+		result.DNSResolved = []string{addr}
+		return nil
+	}
+
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	result.DNSLatency = time.Since(start)
+	if err != nil {
+		return fmt.Errorf("DNS resolution failed: %w", err)
+	}
+	result.DNSResolved = addrs
+	return nil
+}
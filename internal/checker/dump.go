@@ -0,0 +1,199 @@
+// Request dumping
+// Writes outgoing requests in a curl -v like format for debugging
+package checker
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MaskedValue replaces secret values in dumps and other output
+const MaskedValue = "****"
+
+// sensitiveHeaderWords are substrings marking a header as secret
+var sensitiveHeaderWords = []string{"authorization", "cookie", "token", "secret", "password", "api-key", "apikey"}
+
+// maskedParam replaces secret query parameter values
+const maskedParam = "***"
+
+// sensitiveParamWords are substrings marking a query parameter as secret
+var sensitiveParamWords = []string{"key", "token", "secret", "password", "passwd", "auth", "signature", "sig", "credential", "session"}
+
+// WithRequestDump writes each outgoing request line and headers to w before sending
+// Secret header values are masked unless unmask is true
+func WithRequestDump(w io.Writer, unmask bool) Option {
+	return func(c *Checker) {
+		c.dumpWriter = w
+		c.dumpUnmask = unmask
+	}
+}
+
+// Debug is the request sent and response received, for troubleshooting a check
+// Secret header values are masked unless the checker was created with unmask set
+type Debug struct {
+	Request        string      // Request line, e.g. "GET /health HTTP/1.1"
+	RequestHeader  http.Header // Headers sent, including Host
+	Status         string      // Response status line, e.g. "HTTP/1.1 503 Service Unavailable" (empty without a response)
+	ResponseHeader http.Header // Headers received (nil without a response)
+}
+
+// WithDebug records each check's request and response headers in Result.Debug
+// Secret header values are masked unless unmask is true
+func WithDebug(enabled, unmask bool) Option {
+	return func(c *Checker) {
+		c.debug = enabled
+		c.debugUnmask = unmask
+	}
+}
+
+// debugRequest records the request line and headers as sent
+// Secret query parameter values are masked like header values
+func (c *Checker) debugRequest(req *http.Request) *Debug {
+	header := c.debugHeader(req.Header)
+	header.Set("Host", requestHost(req))
+	uri := req.URL.RequestURI()
+	if !c.debugUnmask {
+		uri = MaskURL(uri)
+	}
+	return &Debug{
+		Request:       fmt.Sprintf("%s %s %s", req.Method, uri, req.Proto),
+		RequestHeader: header,
+	}
+}
+
+// debugResponse records the response status line and headers
+func (c *Checker) debugResponse(d *Debug, resp *http.Response) {
+	d.Status = resp.Proto + " " + resp.Status
+	d.ResponseHeader = c.debugHeader(resp.Header)
+}
+
+// debugHeader copies headers, masking secret values unless unmasked
+func (c *Checker) debugHeader(h http.Header) http.Header {
+	out := h.Clone()
+	if out == nil {
+		out = http.Header{}
+	}
+	if c.debugUnmask {
+		return out
+	}
+	for k, values := range out {
+		if !isSensitiveHeader(k) {
+			continue
+		}
+		for i, v := range values {
+			values[i] = maskHeaderValue(v)
+		}
+	}
+	return out
+}
+
+// dumpRequest writes the request line and headers to the dump writer
+func (c *Checker) dumpRequest(req *http.Request) {
+	var b strings.Builder
+
+	uri := req.URL.RequestURI()
+	if !c.dumpUnmask {
+		uri = MaskURL(uri)
+	}
+	fmt.Fprintf(&b, "> %s %s %s\n", req.Method, uri, req.Proto)
+	fmt.Fprintf(&b, "> Host: %s\n", requestHost(req))
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			if !c.dumpUnmask && isSensitiveHeader(k) {
+				v = maskHeaderValue(v)
+			}
+			fmt.Fprintf(&b, "> %s: %s\n", k, v)
+		}
+	}
+	b.WriteString(">\n")
+
+	// Serialize writes so concurrent dumps don't interleave
+	c.dumpMu.Lock()
+	defer c.dumpMu.Unlock()
+	_, _ = io.WriteString(c.dumpWriter, b.String())
+}
+
+// MaskHeader returns a header value with secrets masked, keeping an auth scheme such as "Bearer"
+// Values of headers that don't look secret are returned unchanged
+func MaskHeader(name, value string) string {
+	if isSensitiveHeader(name) {
+		return maskHeaderValue(value)
+	}
+	return value
+}
+
+// isSensitiveHeader reports whether a header likely carries a secret
+func isSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskHeaderValue masks a header value, keeping an auth scheme such as "Bearer"
+func maskHeaderValue(v string) string {
+	if scheme, _, found := strings.Cut(v, " "); found {
+		return scheme + " " + MaskedValue
+	}
+	return MaskedValue
+}
+
+// requestHost returns the Host header a request is sent with: an explicit override or the URL's host
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+// MaskURL masks values of query parameters whose names look secret, e.g. ?api_key=***
+// Parameter order and all other parts of the URL are kept as written
+func MaskURL(raw string) string {
+	base, rest, found := strings.Cut(raw, "?")
+	if !found {
+		return raw
+	}
+	query, fragment, hasFragment := strings.Cut(rest, "#")
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, hasValue := strings.Cut(param, "=")
+		if hasValue && isSensitiveParam(name) {
+			params[i] = name + "=" + maskedParam
+		}
+	}
+
+	masked := base + "?" + strings.Join(params, "&")
+	if hasFragment {
+		masked += "#" + fragment
+	}
+	return masked
+}
+
+// isSensitiveParam reports whether a query parameter name likely carries a secret
+func isSensitiveParam(name string) bool {
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		name = unescaped
+	}
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveParamWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
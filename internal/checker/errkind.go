@@ -0,0 +1,53 @@
+// Error kinds
+// Classifies check failures for aggregate reporting
+package checker
+
+// ErrorKind classifies why a check failed
+type ErrorKind string
+
+// Error kinds
+const (
+	KindNone        ErrorKind = ""             // Check succeeded
+	KindDNS         ErrorKind = "dns"          // Host name could not be resolved
+	KindRefused     ErrorKind = "refused"      // Connection refused
+	KindTimeout     ErrorKind = "timeout"      // Connection or request timed out
+	KindTLS         ErrorKind = "tls"          // Certificate or handshake failure
+	KindClosed      ErrorKind = "closed"       // Server closed the connection without responding
+	KindCanceled    ErrorKind = "canceled"     // Check was canceled
+	KindStatus      ErrorKind = "status"       // Unexpected or forbidden status code
+	KindProto       ErrorKind = "proto"        // Unexpected HTTP protocol version
+	KindLocation    ErrorKind = "location"     // Unexpected redirect target or too many redirects
+	KindContentType ErrorKind = "content_type" // Unexpected response media type
+	KindHeader      ErrorKind = "header"       // Required response header missing or wrong
+	KindBody        ErrorKind = "body"         // Response body mismatch or too large
+	KindCache       ErrorKind = "cache"        // Repeated request was not served from cache
+	KindLatency     ErrorKind = "latency"      // Response slower than the maximum latency
+	KindOther       ErrorKind = "other"        // Any other failure
+)
+
+// IsNetwork reports whether the failure happened before a response was received
+// Such failures may be transient; a wrong response or certificate is not
+func (k ErrorKind) IsNetwork() bool {
+	switch k {
+	case KindDNS, KindRefused, KindTimeout, KindClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CountByKind counts unhealthy results by error kind
+func CountByKind(results []Result) map[ErrorKind]int {
+	counts := make(map[ErrorKind]int)
+	for _, r := range results {
+		if r.Healthy {
+			continue
+		}
+		kind := r.ErrorKind
+		if kind == KindNone {
+			kind = KindOther
+		}
+		counts[kind]++
+	}
+	return counts
+}
@@ -0,0 +1,57 @@
+// Endpoint filtering
+// Selects endpoints to check based on their labels
+package checker
+
+// FilterByTags returns endpoints that have at least one of the given tags
+// If no tags are given, all endpoints are returned
+func FilterByTags(endpoints []Endpoint, tags []string) []Endpoint {
+	if len(tags) == 0 {
+		return endpoints
+	}
+
+	wanted := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		wanted[t] = true
+	}
+
+	filtered := make([]Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		for _, t := range ep.Tags {
+			if wanted[t] {
+				filtered = append(filtered, ep)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// FilterByAllTags returns endpoints that have every one of the given tags
+// If no tags are given, all endpoints are returned
+func FilterByAllTags(endpoints []Endpoint, tags []string) []Endpoint {
+	if len(tags) == 0 {
+		return endpoints
+	}
+
+	filtered := make([]Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		have := make(map[string]bool, len(ep.Tags))
+		for _, t := range ep.Tags {
+			have[t] = true
+		}
+
+		matches := true
+		for _, t := range tags {
+			if !have[t] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, ep)
+		}
+	}
+
+	return filtered
+}
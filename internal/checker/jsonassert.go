@@ -0,0 +1,83 @@
+// JSON body assertions
+// Looks up a field in a JSON response body by dot path and compares its value
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONAssert requires a JSON response field to have a given value
+type JSONAssert struct {
+	Path   string // Dot path to the field, e.g. "status" or "checks.0.state" (numbers index arrays)
+	Equals string // Expected value; non-string values compare by their JSON text, e.g. "true" or "3"
+}
+
+// check verifies the assertion against a response body
+func (a JSONAssert) check(body string) error {
+	got, err := jsonField(body, a.Path)
+	if err != nil {
+		return err
+	}
+	if got != a.Equals {
+		return fmt.Errorf("json field %q: got %q, want %q", a.Path, got, a.Equals)
+	}
+	return nil
+}
+
+// jsonField returns the text of the field at a dot path in a JSON response body
+func jsonField(body, path string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	value, ok := lookupJSON(doc, path)
+	if !ok {
+		return "", fmt.Errorf("json field %q not found", path)
+	}
+	return jsonText(value)
+}
+
+// lookupJSON walks a decoded JSON document along a dot path
+func lookupJSON(doc any, path string) (any, bool) {
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonText returns strings as-is and any other value as compact JSON
+func jsonText(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to encode json field: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
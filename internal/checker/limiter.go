@@ -0,0 +1,131 @@
+// Concurrency limiting
+// Bounds in-flight checks with a fixed or adaptive limit
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// autoConcurrencyStart is the initial limit of the adaptive scheduler
+const autoConcurrencyStart = 2
+
+// limiter bounds the number of checks in flight
+type limiter interface {
+	// acquire waits for a slot, returning false if ctx is done first
+	acquire(ctx context.Context) bool
+	// release frees the slot of a check started at started once its result is known
+	release(ep Endpoint, result Result, started time.Time)
+}
+
+// semaphore is a fixed concurrency limit
+type semaphore chan struct{}
+
+func (s semaphore) acquire(ctx context.Context) bool {
+	select {
+	case s <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s semaphore) release(Endpoint, Result, time.Time) {
+	<-s
+}
+
+// WithAutoConcurrency adapts the concurrency limit to observed backend health
+// The limit starts low and grows by one after each limit's worth of clean checks, up to the
+// WithConcurrency value, and halves when a check times out, is refused, or takes over half its timeout
+func WithAutoConcurrency(enabled bool) Option {
+	return func(c *Checker) {
+		c.autoConcurrency = enabled
+	}
+}
+
+// adaptiveLimiter is an additive-increase, multiplicative-decrease concurrency limit
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	max      int
+	inFlight int
+	clean    int // Clean completions since the limit last changed
+
+	// Checks started before the last decrease don't trigger another one,
+	// so a burst of failures from one overloaded window halves the limit once
+	lastDecrease time.Time
+}
+
+// newAdaptiveLimiter creates an adaptive limit bounded by max
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	l := &adaptiveLimiter{
+		limit: min(autoConcurrencyStart, max),
+		max:   max,
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *adaptiveLimiter) acquire(ctx context.Context) bool {
+	// Wake waiters when the batch is canceled
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= l.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		l.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+func (l *adaptiveLimiter) release(ep Endpoint, result Result, started time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+
+	if congested(ep, result) {
+		if started.After(l.lastDecrease) {
+			l.limit = max(l.limit/2, 1)
+			l.clean = 0
+			l.lastDecrease = time.Now()
+		}
+	} else {
+		l.clean++
+		if l.clean >= l.limit && l.limit < l.max {
+			l.limit++
+			l.clean = 0
+		}
+	}
+
+	l.cond.Broadcast()
+}
+
+// Limit returns the current concurrency limit
+func (l *adaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// congested reports whether a result suggests the backends are overloaded
+func congested(ep Endpoint, result Result) bool {
+	switch result.ErrorKind {
+	case KindTimeout, KindRefused, KindClosed:
+		return true
+	}
+	return ep.Timeout > 0 && result.Latency > ep.Timeout/2
+}
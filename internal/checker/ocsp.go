@@ -0,0 +1,174 @@
+// OCSP revocation check
+// Queries the responder named in the served certificate (RFC 6960)
+package checker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// OCSPStatus is the revocation status an OCSP responder reports for a certificate
+type OCSPStatus string
+
+// OCSP statuses
+const (
+	OCSPGood    OCSPStatus = "good"    // Not revoked
+	OCSPRevoked OCSPStatus = "revoked" // Revoked by its issuer
+	OCSPUnknown OCSPStatus = "unknown" // Responder doesn't know the certificate
+)
+
+// maxOCSPResponseSize limits the responder's answer; real responses are a few KB
+const maxOCSPResponseSize = 64 << 10
+
+// ocspClient queries responders directly: the endpoint's client certificate, proxy,
+// resolve overrides and redirect settings are for the endpoint, not its CA
+var ocspClient = &http.Client{}
+
+// WithOCSP queries each HTTPS endpoint's OCSP responder after the response
+// A revoked certificate fails the check; an unknown status or failed query adds a warning
+func WithOCSP(enabled bool) Option {
+	return func(c *Checker) {
+		c.ocsp = enabled
+	}
+}
+
+// ocspResponseCertIDs holds the CertIDs of an OCSP ResponseData; the rest of each
+// SingleResponse is parsed by the ocsp package
+type ocspResponseCertIDs struct {
+	Version     int `asn1:"optional,default:0,explicit,tag:0"`
+	ResponderID asn1.RawValue
+	ProducedAt  asn1.RawValue
+	Responses   []struct {
+		CertID struct {
+			HashAlgorithm  pkix.AlgorithmIdentifier
+			IssuerNameHash []byte
+			IssuerKeyHash  []byte
+			SerialNumber   *big.Int
+		}
+	}
+}
+
+// queryOCSP asks the leaf certificate's OCSP responder for its status
+// The revocation time is set for revoked certificates
+func queryOCSP(ctx context.Context, state *tls.ConnectionState) (OCSPStatus, time.Time, error) {
+	if len(state.PeerCertificates) == 0 {
+		return "", time.Time{}, errors.New("server sent no certificates")
+	}
+	leaf := state.PeerCertificates[0]
+	if len(leaf.OCSPServer) == 0 {
+		return "", time.Time{}, errors.New("certificate names no OCSP responder")
+	}
+	issuer := ocspIssuer(state)
+	if issuer == nil {
+		return "", time.Time{}, errors.New("issuer certificate not available")
+	}
+
+	der, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode OCSP request: %w", err)
+	}
+	certID, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode OCSP request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, leaf.OCSPServer[0], bytes.NewReader(der))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid OCSP responder: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	resp, err := ocspClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("OCSP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("OCSP responder returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read OCSP response: %w", err)
+	}
+
+	return parseOCSPResponse(data, certID, issuer, time.Now())
+}
+
+// ocspIssuer returns the leaf's issuer from the verified chain, or from the certificates the server sent
+func ocspIssuer(state *tls.ConnectionState) *x509.Certificate {
+	for _, chain := range state.VerifiedChains {
+		if len(chain) > 1 {
+			return chain[1]
+		}
+	}
+	if len(state.PeerCertificates) > 1 && state.PeerCertificates[0].CheckSignatureFrom(state.PeerCertificates[1]) == nil {
+		return state.PeerCertificates[1]
+	}
+	return nil
+}
+
+// parseOCSPResponse verifies a responder's answer and returns the status of the certificate certID names
+// The response must be signed by issuer or by a responder certificate issuer delegated OCSP signing to,
+// and its CertID must match the serial number and issuer name and key hashes of the request
+func parseOCSPResponse(data []byte, certID *ocsp.Request, issuer *x509.Certificate, now time.Time) (OCSPStatus, time.Time, error) {
+	resp, err := ocsp.ParseResponseForCert(data, &x509.Certificate{SerialNumber: certID.SerialNumber}, issuer)
+	if err != nil {
+		var respErr ocsp.ResponseError
+		if errors.As(err, &respErr) {
+			return "", time.Time{}, fmt.Errorf("OCSP responder returned error status: %s", respErr.Status)
+		}
+		return "", time.Time{}, fmt.Errorf("invalid OCSP response: %w", err)
+	}
+
+	if resp.Certificate != nil && !bytes.Equal(resp.Certificate.Raw, issuer.Raw) &&
+		!slices.Contains(resp.Certificate.ExtKeyUsage, x509.ExtKeyUsageOCSPSigning) {
+		return "", time.Time{}, errors.New("OCSP responder certificate is not authorized for OCSP signing")
+	}
+	if err := checkOCSPIssuer(resp.TBSResponseData, certID); err != nil {
+		return "", time.Time{}, err
+	}
+	if !resp.NextUpdate.IsZero() && now.After(resp.NextUpdate) {
+		return "", time.Time{}, fmt.Errorf("OCSP response expired at %s", resp.NextUpdate.Format(time.RFC3339))
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return OCSPGood, time.Time{}, nil
+	case ocsp.Revoked:
+		return OCSPRevoked, resp.RevokedAt, nil
+	default:
+		return OCSPUnknown, time.Time{}, nil
+	}
+}
+
+// checkOCSPIssuer verifies the first response for certID's serial names the same issuer
+// Serial numbers are only unique per issuer, so a match by serial alone could be another CA's certificate
+func checkOCSPIssuer(tbs []byte, certID *ocsp.Request) error {
+	var data ocspResponseCertIDs
+	if _, err := asn1.Unmarshal(tbs, &data); err != nil {
+		return fmt.Errorf("invalid OCSP response: %w", err)
+	}
+	for _, single := range data.Responses {
+		if single.CertID.SerialNumber.Cmp(certID.SerialNumber) != 0 {
+			continue
+		}
+		if !bytes.Equal(single.CertID.IssuerNameHash, certID.IssuerNameHash) ||
+			!bytes.Equal(single.CertID.IssuerKeyHash, certID.IssuerKeyHash) {
+			return errors.New("OCSP response is for a certificate of another issuer")
+		}
+		return nil
+	}
+	return errors.New("OCSP response does not cover the certificate")
+}
@@ -0,0 +1,42 @@
+// PKCS#12 client certificates
+// Decodes .p12/.pfx bundles into TLS client certificates
+package checker
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// errIncorrectPassword is returned when the bundle MAC or decryption fails
+var errIncorrectPassword = errors.New("incorrect password")
+
+// LoadPKCS12 reads a PKCS#12 bundle and returns its client certificate and private key
+// Any CA certificates in the bundle are sent along as the rest of the chain
+func LoadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+
+	key, leaf, caCerts, err := pkcs12.DecodeChain(data, password)
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		err = errIncorrectPassword
+	}
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid PKCS#12 bundle %s: %w", path, err)
+	}
+
+	cert := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, ca := range caCerts {
+		cert.Certificate = append(cert.Certificate, ca.Raw)
+	}
+	return cert, nil
+}
@@ -0,0 +1,59 @@
+// Per-host rate limiting
+// Spaces out requests so each host sees at most a fixed request rate
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRatePerHost limits how many requests per second are sent to each host
+// Requests to the same host are at least 1/rps apart, without bursts, including retries
+// and later batches of the same Checker; 0 disables the limit
+// The wait does not count against the endpoint timeout and holds no concurrency slot
+func WithRatePerHost(rps float64) Option {
+	return func(c *Checker) {
+		if rps > 0 {
+			c.ratePerHost = rps
+		}
+	}
+}
+
+// waitRate blocks until the endpoint's host allows another request
+func (c *Checker) waitRate(ctx context.Context, ep Endpoint) error {
+	if c.ratePerHost <= 0 {
+		return nil
+	}
+
+	host := rateLimitHost(ep.URL)
+	c.rateMu.Lock()
+	lim, ok := c.rateLimiters[host]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(c.ratePerHost), 1)
+		c.rateLimiters[host] = lim
+	}
+	c.rateMu.Unlock()
+	return lim.Wait(ctx)
+}
+
+// rateLimitResult reports a check that gave up waiting for its host's rate limit
+func rateLimitResult(ctx context.Context, ep Endpoint, err error) Result {
+	kind := KindTimeout
+	if ctx.Err() != nil {
+		kind = contextErrorKind(ctx.Err())
+	}
+	return Result{Name: ep.Name, URL: ep.URL, Error: fmt.Errorf("rate limit: %w", err), ErrorKind: kind}
+}
+
+// rateLimitHost returns the host and port a URL is rate limited by, or the URL itself if unparseable
+func rateLimitHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.ToLower(u.Host)
+}
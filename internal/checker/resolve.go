@@ -0,0 +1,89 @@
+// Address overrides
+// Connects to a chosen address while keeping the URL host for Host and SNI, like curl --resolve
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseResolve parses a curl-style "host:port:addr" override
+// It returns the lowercase "host:port" it applies to and the "addr:port" to dial instead;
+// IPv6 addresses are written in brackets, e.g. "api.example.com:443:[2001:db8::1]"
+func ParseResolve(entry string) (string, string, error) {
+	host, rest, ok := strings.Cut(entry, ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid resolve '%s': must be host:port:addr", entry)
+	}
+	port, addr, ok := strings.Cut(rest, ":")
+	if !ok || host == "" || addr == "" {
+		return "", "", fmt.Errorf("invalid resolve '%s': must be host:port:addr", entry)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid resolve '%s': port must be 1-65535", entry)
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	if net.ParseIP(addr) == nil {
+		return "", "", fmt.Errorf("invalid resolve '%s': '%s' is not an IP address", entry, addr)
+	}
+	return net.JoinHostPort(strings.ToLower(host), port), net.JoinHostPort(addr, port), nil
+}
+
+// resolveOverrides maps each overridden "host:port" to the address dialed instead
+// The first entry for a host and port wins
+func (ep Endpoint) resolveOverrides() (map[string]string, error) {
+	if len(ep.Resolve) == 0 {
+		return nil, nil
+	}
+	overrides := make(map[string]string, len(ep.Resolve))
+	for _, entry := range ep.Resolve {
+		hostPort, target, err := ParseResolve(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := overrides[hostPort]; !ok {
+			overrides[hostPort] = target
+		}
+	}
+	return overrides, nil
+}
+
+// dialContext returns the dialer's DialContext with the endpoint's Resolve overrides applied
+// Only the connection target changes; the request keeps the URL host for Host and TLS ServerName
+func (ep Endpoint) dialContext(dialer *net.Dialer) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	overrides, err := ep.resolveOverrides()
+	if err != nil || overrides == nil {
+		return dialer.DialContext, err
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if target, ok := overrides[strings.ToLower(addr)]; ok {
+			addr = target
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}, nil
+}
+
+// resolvedAddr returns the override address for the URL's host and port, if any
+func (ep Endpoint) resolvedAddr(u *url.URL) (string, bool) {
+	overrides, err := ep.resolveOverrides()
+	if err != nil || overrides == nil {
+		return "", false
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	target, ok := overrides[net.JoinHostPort(strings.ToLower(u.Hostname()), port)]
+	if !ok {
+		return "", false
+	}
+	host, _, _ := net.SplitHostPort(target)
+	return host, true
+}
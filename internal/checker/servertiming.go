@@ -0,0 +1,72 @@
+// Server-Timing header parsing
+// Extracts server-reported processing durations from responses
+package checker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerTimingMetric is a single metric from a Server-Timing header
+type ServerTimingMetric struct {
+	Name        string        // Metric name
+	Duration    time.Duration // Reported duration (zero if not given)
+	Description string        // Optional description
+}
+
+// parseServerTiming parses all Server-Timing headers of a response
+// Format: metric;dur=12.3;desc="Description", other;dur=4
+func parseServerTiming(header http.Header) []ServerTimingMetric {
+	var metrics []ServerTimingMetric
+
+	for _, value := range header.Values("Server-Timing") {
+		for _, entry := range splitQuoted(value, ',') {
+			params := splitQuoted(entry, ';')
+			name := strings.TrimSpace(params[0])
+			if name == "" {
+				continue
+			}
+
+			metric := ServerTimingMetric{Name: name}
+			for _, p := range params[1:] {
+				key, val, _ := strings.Cut(p, "=")
+				val = strings.Trim(strings.TrimSpace(val), `"`)
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "dur":
+					if ms, err := strconv.ParseFloat(val, 64); err == nil {
+						metric.Duration = time.Duration(ms * float64(time.Millisecond))
+					}
+				case "desc":
+					metric.Description = val
+				}
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+// splitQuoted splits s on sep, ignoring separators inside double quotes
+func splitQuoted(s string, sep rune) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case r == sep && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(parts, current.String())
+}
@@ -0,0 +1,55 @@
+// Result ordering
+// Implements sorting of check results for triage views
+package checker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortKey is the metric used to order results
+type SortKey string
+
+const (
+	SortByLatency SortKey = "latency"
+	SortByFailure SortKey = "failure"
+)
+
+// SortResults sorts results in place, worst first
+// latency: slowest first; failure: unhealthy first (no response before bad status), then slowest
+func SortResults(results []Result, key SortKey) error {
+	var less func(a, b Result) bool
+
+	switch key {
+	case SortByLatency:
+		less = func(a, b Result) bool {
+			return a.Latency > b.Latency
+		}
+	case SortByFailure:
+		less = func(a, b Result) bool {
+			if fa, fb := failureRank(a), failureRank(b); fa != fb {
+				return fa > fb
+			}
+			return a.Latency > b.Latency
+		}
+	default:
+		return fmt.Errorf("unknown sort key '%s': must be latency or failure", key)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return less(results[i], results[j])
+	})
+	return nil
+}
+
+// failureRank ranks how badly a result failed: 2 no response, 1 bad response, 0 healthy
+func failureRank(r Result) int {
+	switch {
+	case r.Healthy:
+		return 0
+	case r.StatusCode != nil:
+		return 1
+	default:
+		return 2
+	}
+}
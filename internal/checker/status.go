@@ -0,0 +1,64 @@
+// Status code matching
+// Parses expected status classes and ranges such as 2xx or 200-299
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusRange is an inclusive range of accepted status codes
+// A single code has Min equal to Max
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// ParseStatusRange parses a status code ("200"), class ("2xx") or inclusive range ("200-299")
+// Codes are not checked against 100-599 here
+func ParseStatusRange(s string) (StatusRange, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 3 && strings.EqualFold(s[1:], "xx") {
+		if s[0] < '1' || s[0] > '9' {
+			return StatusRange{}, fmt.Errorf("invalid status class '%s' (want e.g. 2xx)", s)
+		}
+		base := int(s[0]-'0') * 100
+		return StatusRange{Min: base, Max: base + 99}, nil
+	}
+
+	if low, high, ok := strings.Cut(s, "-"); ok {
+		lo, errLo := strconv.Atoi(strings.TrimSpace(low))
+		hi, errHi := strconv.Atoi(strings.TrimSpace(high))
+		if errLo != nil || errHi != nil {
+			return StatusRange{}, fmt.Errorf("invalid status range '%s' (want e.g. 200-299)", s)
+		}
+		if lo > hi {
+			return StatusRange{}, fmt.Errorf("invalid status range '%s': %d is greater than %d", s, lo, hi)
+		}
+		return StatusRange{Min: lo, Max: hi}, nil
+	}
+
+	code, err := strconv.Atoi(s)
+	if err != nil {
+		return StatusRange{}, fmt.Errorf("invalid status '%s' (want a code, class like 2xx or range like 200-299)", s)
+	}
+	return StatusRange{Min: code, Max: code}, nil
+}
+
+// Contains reports whether code is in the range
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// String formats the range as parsed: "200", "2xx" for a whole class, otherwise "200-299"
+func (r StatusRange) String() string {
+	switch {
+	case r.Min == r.Max:
+		return strconv.Itoa(r.Min)
+	case r.Min%100 == 0 && r.Max == r.Min+99:
+		return fmt.Sprintf("%dxx", r.Min/100)
+	default:
+		return fmt.Sprintf("%d-%d", r.Min, r.Max)
+	}
+}
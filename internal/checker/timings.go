@@ -0,0 +1,91 @@
+// Connection phase timings
+// Breaks a check's latency down into DNS, TCP connect, TLS handshake and time to first byte
+package checker
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings are the connection phases of a check
+// DNS, Connect and TLS are zero when an idle keep-alive connection was reused
+type Timings struct {
+	DNS        time.Duration // Host name resolution
+	Connect    time.Duration // TCP connection establishment
+	TLS        time.Duration // TLS handshake
+	TTFB       time.Duration // From sending the request to the first response byte
+	ConnReused bool          // Whether an idle connection was reused
+}
+
+// phaseTrace records phase timestamps from httptrace callbacks, which may run on dialer goroutines
+// Only the first occurrence of each phase counts, so a Digest retry doesn't overwrite the initial request
+type phaseTrace struct {
+	mu                        sync.Mutex
+	seen                      bool
+	reused                    bool
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+}
+
+// hook adds the phase callbacks to trace, keeping its existing GotConn callback
+func (p *phaseTrace) hook(trace *httptrace.ClientTrace) {
+	record := func(t *time.Time) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.seen = true
+		if t.IsZero() {
+			*t = time.Now()
+		}
+	}
+
+	gotConn := trace.GotConn
+	trace.GotConn = func(info httptrace.GotConnInfo) {
+		p.mu.Lock()
+		if !p.seen {
+			p.reused = info.Reused
+		}
+		p.seen = true
+		p.mu.Unlock()
+		if gotConn != nil {
+			gotConn(info)
+		}
+	}
+	trace.DNSStart = func(httptrace.DNSStartInfo) { record(&p.dnsStart) }
+	trace.DNSDone = func(httptrace.DNSDoneInfo) { record(&p.dnsDone) }
+	trace.ConnectStart = func(string, string) { record(&p.connectStart) }
+	trace.ConnectDone = func(_, _ string, err error) {
+		if err == nil {
+			record(&p.connectDone)
+		}
+	}
+	trace.TLSHandshakeStart = func() { record(&p.tlsStart) }
+	trace.TLSHandshakeDone = func(tls.ConnectionState, error) { record(&p.tlsDone) }
+	trace.WroteRequest = func(httptrace.WroteRequestInfo) { record(&p.wroteRequest) }
+	trace.GotFirstResponseByte = func() { record(&p.firstByte) }
+}
+
+// timings returns the recorded phases of the request, or nil if no connection was attempted
+func (p *phaseTrace) timings() *Timings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.seen {
+		return nil
+	}
+	span := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	return &Timings{
+		DNS:        span(p.dnsStart, p.dnsDone),
+		Connect:    span(p.connectStart, p.connectDone),
+		TLS:        span(p.tlsStart, p.tlsDone),
+		TTFB:       span(p.wroteRequest, p.firstByte),
+		ConnReused: p.reused,
+	}
+}
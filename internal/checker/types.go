@@ -3,6 +3,7 @@
 package checker
 
 import (
+	"regexp"
 	"time"
 )
 
@@ -11,32 +12,158 @@ var Version = "dev"
 
 // Endpoint represents an endpoint to check
 type Endpoint struct {
-	Name            string            // Endpoint name for display
-	URL             string            // URL to check
-	Timeout         time.Duration     // Request timeout
-	Retries         int               // Retry count on failure
-	ExpectedStatus  int               // Expected HTTP status code
-	FollowRedirects bool              // Whether to follow redirects
-	Insecure        bool              // Whether to skip SSL verification
-	Headers         map[string]string // Custom request headers
+	Name                string            // Endpoint name for display
+	URL                 string            // URL to check
+	Method              string            // HTTP request method (empty for GET)
+	Timeout             time.Duration     // Request timeout
+	DegradedLatency     time.Duration     // Latency above which a healthy response is degraded (0 to skip)
+	MaxLatency          time.Duration     // Latency above which the endpoint is down (0 to skip)
+	Retries             int               // Retry count on failure
+	ExpectedStatus      int               // Expected HTTP status code (0 accepts any)
+	ExpectedStatuses    []int             // Accepted status codes, overriding ExpectedStatus when set
+	ExpectedRanges      []StatusRange     // Accepted status classes or ranges, with ExpectedStatuses overriding ExpectedStatus
+	StatusByMethod      map[string]int    // Expected status per request method, overriding ExpectedStatus
+	ForbiddenStatus     []int             // Status codes that mark the endpoint unhealthy
+	ExpectedProto       string            // Expected response protocol such as "HTTP/2.0" (empty to skip)
+	FollowRedirects     bool              // Whether to follow redirects
+	Insecure            bool              // Whether to skip SSL verification
+	ConnectOnly         bool              // Healthy once the TCP/TLS connection is established, response ignored
+	TLSServerName       string            // SNI server name overriding the URL host (empty to use the host)
+	TLSALPN             []string          // ALPN protocols offered in the TLS handshake (empty for Go's default)
+	CACert              string            // PEM file of the only CAs trusted, replacing the system pool (empty for the system pool)
+	AddCACert           string            // PEM file of extra CAs trusted alongside the system pool
+	CompleteChain       bool              // Fail unless the server sends every intermediate certificate itself
+	ClientCertP12       string            // PKCS#12 bundle with the TLS client certificate and key (empty for none)
+	ClientCertP12Pass   string            // Password of the PKCS#12 bundle
+	ClientCert          string            // PEM TLS client certificate, paired with ClientKey (empty for none)
+	ClientKey           string            // PEM private key of ClientCert
+	Proxy               string            // Proxy URL (empty to use HTTP_PROXY, HTTPS_PROXY and NO_PROXY)
+	NoProxy             bool              // Connect directly, ignoring Proxy and the proxy environment variables
+	MaxRedirects        int               // Redirects followed before failing (0 for Go's default of 10)
+	Resolve             []string          // curl-style "host:port:addr" dial overrides; Host and SNI keep the URL host
+	MaxHeaderBytes      int64             // Response header size limit (0 for Go's default)
+	Headers             map[string]string // Custom request headers
+	Body                string            // Request payload (empty to send none)
+	AuthType            string            // AuthBasic, AuthDigest or AuthAWSv4 (empty for no authentication)
+	Username            string            // Authentication user name
+	Password            string            // Authentication password
+	AWSRegion           string            // SigV4 signing region, e.g. us-east-1
+	AWSService          string            // SigV4 signing service, e.g. execute-api
+	Tags                []string          // Labels used for filtering
+	Accept              string            // Media type requested with Accept; the response Content-Type must match it (empty to skip)
+	RequireContentType  string            // Required response media type, checked before body assertions
+	HeaderAssert        map[string]string // Required response headers and the value each must equal (empty only requires presence)
+	ExpectedBody        *string           // Exact expected response body (nil to skip)
+	NormalizeWhitespace bool              // Collapse whitespace before comparing ExpectedBody
+	ExpectedBodySHA256  string            // Hex SHA-256 the response body must hash to (empty to skip)
+	BodyContains        string            // Substring the response body must contain (empty to skip)
+	BodyRegex           *regexp.Regexp    // Pattern the response body must match (nil to skip)
+	JSONAssert          *JSONAssert       // JSON field the response body must contain (nil to skip)
+	Capture             map[string]string // Dot paths into the JSON response captured as {{name}} for later endpoints
+	MinBodyBytes        int               // Minimum response body size (0 to skip)
+	MaxBodyBytes        int               // Maximum response body size (0 to skip)
+	ExpectedLocation    string            // Exact expected Location header on redirects
+	LocationPattern     *regexp.Regexp    // Pattern the Location header must match on redirects
+	Schedule            string            // Cron expression used by serve mode (empty for the default)
+}
+
+// Methods lists the HTTP methods an endpoint may use
+var Methods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// method returns the request method, defaulting to GET
+func (ep Endpoint) method() string {
+	if ep.Method == "" {
+		return "GET"
+	}
+	return ep.Method
+}
+
+// expectedStatusesFor returns the accepted statuses for the method actually sent (empty accepts any)
+func (ep Endpoint) expectedStatusesFor(method string) []StatusRange {
+	if code, ok := ep.StatusByMethod[method]; ok {
+		return []StatusRange{{Min: code, Max: code}}
+	}
+	if len(ep.ExpectedStatuses) > 0 || len(ep.ExpectedRanges) > 0 {
+		expected := make([]StatusRange, 0, len(ep.ExpectedStatuses)+len(ep.ExpectedRanges))
+		for _, code := range ep.ExpectedStatuses {
+			expected = append(expected, StatusRange{Min: code, Max: code})
+		}
+		return append(expected, ep.ExpectedRanges...)
+	}
+	if ep.ExpectedStatus != 0 {
+		return []StatusRange{{Min: ep.ExpectedStatus, Max: ep.ExpectedStatus}}
+	}
+	return nil
 }
 
 // Result represents health check result
 type Result struct {
-	Name       string        // Endpoint name
-	URL        string        // Checked URL
-	Healthy    bool          // Whether healthy
-	StatusCode *int          // HTTP status code (nil if connection failed)
-	Latency    time.Duration // Response latency
-	Error      error         // Error message
+	Name         string               // Endpoint name
+	URL          string               // Checked URL
+	Healthy      bool                 // Whether healthy
+	Degraded     bool                 // Healthy but slower than DegradedLatency
+	StatusCode   *int                 // HTTP status code (nil if connection failed)
+	Latency      time.Duration        // Response latency
+	LatencyEMA   time.Duration        // Moving average of latency across watch cycles (0 unless tracked)
+	CheckedAt    time.Time            // When the check started (zero unless recording timestamps)
+	CompletedAt  time.Time            // When the check finished (zero unless recording timestamps)
+	Error        error                // Error message
+	ErrorKind    ErrorKind            // Failure classification (empty when healthy)
+	FinalURL     string               // URL the redirects led to (empty unless a redirect was followed)
+	Redirects    []string             // URL of each followed redirect in order, ending with FinalURL
+	ALPN         string               // Negotiated ALPN protocol (empty unless TLSALPN is set)
+	Cert         *CertInfo            // Served certificate (nil without TLS or unless recording certificates)
+	OCSP         OCSPStatus           // Revocation status from the OCSP responder (empty unless checked and answered)
+	DNSResolved  []string             // Addresses the host resolved to (nil unless probing DNS)
+	DNSLatency   time.Duration        // Time spent resolving the host when probing DNS
+	Timings      *Timings             // Connection phase breakdown (nil if no connection was attempted)
+	BodySHA256   string               // Hex SHA-256 of the response body (empty unless hashing bodies)
+	Warnings     []string             // Non-fatal findings that don't affect Healthy
+	Assertions   []AssertionResult    // Outcome of each assertion run; Healthy requires all to pass
+	ServerTiming []ServerTimingMetric // Server-reported durations from Server-Timing headers
+	Cache        *CacheResult         // Cold and warm request details (nil unless cache checking)
+	Captured     map[string]string    // Values captured for later endpoints, keyed by lowercase name
+	Debug        *Debug               // Request and response headers (nil unless recording debug detail)
+}
+
+// State is the tri-state health of a result
+type State string
+
+// Result states
+const (
+	StateHealthy  State = "healthy"  // Healthy within latency targets
+	StateDegraded State = "degraded" // Up but slower than the degraded latency
+	StateDown     State = "down"     // Failed or slower than the maximum latency
+)
+
+// State returns the result's tri-state health
+func (r Result) State() State {
+	switch {
+	case !r.Healthy:
+		return StateDown
+	case r.Degraded:
+		return StateDegraded
+	default:
+		return StateHealthy
+	}
 }
 
 // Summary represents batch check summary
 type Summary struct {
-	Total     int           // Total endpoints
-	Healthy   int           // Healthy count
-	Unhealthy int           // Unhealthy count
-	Duration  time.Duration // Total duration
+	Total          int               // Total endpoints
+	Healthy        int               // Healthy count, including degraded
+	Degraded       int               // Degraded count
+	Unhealthy      int               // Unhealthy count
+	Duration       time.Duration     // Total duration
+	Concurrency    int               // Final adaptive concurrency limit (0 unless auto-tuned)
+	FailuresByKind map[ErrorKind]int // Unhealthy count per error kind (nil unless requested)
+	Slowest        *Slowest          // Endpoint that took the longest (nil without results)
+}
+
+// Slowest identifies the endpoint with the highest latency in a batch
+type Slowest struct {
+	Name    string        // Endpoint name
+	Latency time.Duration // Its latency, including time spent on a failed attempt
 }
 
 // BatchResult represents complete batch check result
@@ -46,11 +173,18 @@ type BatchResult struct {
 	Results   []Result  // Detailed results
 }
 
+// ConnStats represents connection pool usage across checks
+type ConnStats struct {
+	New    int64 // Newly established connections
+	Reused int64 // Connections reused from the idle pool
+}
+
 // DefaultEndpoint creates an endpoint with default config
 func DefaultEndpoint(url string) Endpoint {
 	return Endpoint{
 		Name:            url,
 		URL:             url,
+		Method:          "GET",
 		Timeout:         5 * time.Second,
 		Retries:         0,
 		ExpectedStatus:  200,
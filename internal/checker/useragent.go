@@ -0,0 +1,67 @@
+// User-Agent rotation
+// Varies the User-Agent across requests to avoid per-UA rate limits
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultUserAgents are common desktop and mobile browser User-Agents used for rotation
+var DefaultUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15",
+	"Mozilla/5.0 (X11; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Mobile Safari/537.36",
+}
+
+// WithUserAgents picks a random User-Agent from agents for each request
+// An explicit User-Agent header on the endpoint still takes precedence
+func WithUserAgents(agents []string) Option {
+	return func(c *Checker) {
+		c.userAgents = agents
+	}
+}
+
+// LoadUserAgents reads one User-Agent per line, skipping blank lines and # comments
+func LoadUserAgents(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user agent file: %w", err)
+	}
+	defer f.Close()
+
+	var agents []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		agents = append(agents, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read user agent file: %w", err)
+	}
+
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("no user agents found in %s", path)
+	}
+	return agents, nil
+}
+
+// userAgent returns the User-Agent for the next request
+func (c *Checker) userAgent() string {
+	if len(c.userAgents) == 0 {
+		return "healthcheck-cli/" + Version
+	}
+
+	c.rngMu.Lock()
+	defer c.rngMu.Unlock()
+	return c.userAgents[c.rng.Intn(len(c.userAgents))]
+}
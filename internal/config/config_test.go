@@ -3,11 +3,16 @@
 package config
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
 )
 
 // TestLoad_Success tests successful config file loading
@@ -77,6 +82,154 @@ endpoints:
 	}
 }
 
+// TestLoadRegistry tests loading a service registry export
+func TestLoadRegistry(t *testing.T) {
+	content := `[
+  {"name": "billing", "healthUrl": "https://billing.internal/healthz"},
+  {"name": "search", "healthUrl": "https://search.internal/healthz", "owner": "team-search"}
+]`
+	cfg, err := LoadRegistry(createTempFile(t, "services.json", content))
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	if len(endpoints) != 2 {
+		t.Fatalf("len(endpoints) = %d, want 2", len(endpoints))
+	}
+	if endpoints[1].Name != "search" || endpoints[1].URL != "https://search.internal/healthz" {
+		t.Errorf("endpoints[1] = %s %s, want search https://search.internal/healthz", endpoints[1].Name, endpoints[1].URL)
+	}
+	// Registry entries use global defaults
+	if endpoints[0].Timeout != 5*time.Second || endpoints[0].ExpectedStatus != 200 {
+		t.Errorf("endpoints[0] Timeout = %v ExpectedStatus = %d, want defaults", endpoints[0].Timeout, endpoints[0].ExpectedStatus)
+	}
+}
+
+// TestLoadRegistry_Errors tests registry loading failures
+func TestLoadRegistry_Errors(t *testing.T) {
+	if _, err := LoadRegistry("/nonexistent/services.json"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("LoadRegistry(missing) error = %v, want not found", err)
+	}
+
+	if _, err := LoadRegistry(createTempFile(t, "services.json", `{"name": "not a list"}`)); err == nil {
+		t.Error("LoadRegistry(invalid) error = nil, want error")
+	}
+}
+
+// TestFromResults tests building a config from checked endpoints
+func TestFromResults(t *testing.T) {
+	endpoints := []checker.Endpoint{
+		checker.DefaultEndpoint("https://a.example.com/health"),
+		checker.DefaultEndpoint("https://b.example.com/health"),
+		checker.DefaultEndpoint("https://c.example.com/health"),
+	}
+	endpoints[1].Timeout = 10 * time.Second
+	endpoints[1].Insecure = true
+	endpoints[1].Headers = map[string]string{"X-Env": "staging"}
+
+	created := 201
+	results := []checker.Result{
+		{StatusCode: &created},
+		{},
+		{},
+	}
+
+	cfg := FromResults(endpoints, results)
+	path := filepath.Join(t.TempDir(), "saved.yaml")
+	data, err := Marshal(cfg, FormatYAML)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The saved config loads back with the inferred settings
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := loaded.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len(endpoints) = %d, want 3", len(got))
+	}
+	if got[0].ExpectedStatus != 201 {
+		t.Errorf("endpoints[0].ExpectedStatus = %d, want 201", got[0].ExpectedStatus)
+	}
+	// Viper lowercases header names on load
+	if got[1].ExpectedStatus != 200 || got[1].Timeout != 10*time.Second || !got[1].Insecure || got[1].Headers["x-env"] != "staging" {
+		t.Errorf("endpoints[1] = %+v, want 200, 10s, insecure, X-Env header", got[1])
+	}
+	if cfg.Endpoints[2].Timeout != "" || cfg.Endpoints[2].Insecure != nil {
+		t.Errorf("Endpoints[2] = %+v, want default settings omitted", cfg.Endpoints[2])
+	}
+}
+
+// TestParseURLList tests per-line settings in URL lists
+func TestParseURLList(t *testing.T) {
+	input := `# ad-hoc checks
+https://a.example.com/health
+
+https://b.example.com/health 10s 204
+https://c.example.com/health 201
+`
+	cfg, err := ParseURLList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseURLList() error = %v", err)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	tests := []struct {
+		url     string
+		timeout time.Duration
+		status  int
+	}{
+		{"https://a.example.com/health", 5 * time.Second, 200},
+		{"https://b.example.com/health", 10 * time.Second, 204},
+		{"https://c.example.com/health", 5 * time.Second, 201},
+	}
+	if len(endpoints) != len(tests) {
+		t.Fatalf("len(endpoints) = %d, want %d", len(endpoints), len(tests))
+	}
+	for i, tt := range tests {
+		ep := endpoints[i]
+		if ep.URL != tt.url || ep.Name != tt.url || ep.Timeout != tt.timeout || ep.ExpectedStatus != tt.status {
+			t.Errorf("endpoints[%d] = %s %v %d, want %s %v %d", i, ep.URL, ep.Timeout, ep.ExpectedStatus, tt.url, tt.timeout, tt.status)
+		}
+	}
+}
+
+// TestParseURLList_Errors tests malformed URL list lines
+func TestParseURLList_Errors(t *testing.T) {
+	tests := []struct {
+		input       string
+		errContains string
+	}{
+		{"https://a.example.com fast", "line 1: 'fast' is neither"},
+		{"https://a.example.com\nhttps://b.example.com 200 204", "line 2: duplicate expected status"},
+		{"https://a.example.com 1s 2s", "duplicate timeout"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseURLList(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("ParseURLList(%q) error = %v, want to contain %q", tt.input, err, tt.errContains)
+		}
+	}
+}
+
 // TestToCheckerEndpoints_Basic tests basic config conversion
 func TestToCheckerEndpoints_Basic(t *testing.T) {
 	cfg := &Config{
@@ -161,7 +314,6 @@ func TestToCheckerEndpoints_WithDefaults(t *testing.T) {
 // TestToCheckerEndpoints_EndpointOverridesDefaults tests endpoint config overrides defaults
 func TestToCheckerEndpoints_EndpointOverridesDefaults(t *testing.T) {
 	retries := 5
-	expectedStatus := 204
 	insecure := true
 	cfg := &Config{
 		Defaults: Defaults{
@@ -175,7 +327,7 @@ func TestToCheckerEndpoints_EndpointOverridesDefaults(t *testing.T) {
 				URL:            "https://example.com",
 				Timeout:        "30s",
 				Retries:        &retries,
-				ExpectedStatus: &expectedStatus,
+				ExpectedStatus: []string{"204"},
 				Insecure:       &insecure,
 			},
 		},
@@ -272,11 +424,563 @@ func TestToCheckerEndpoints_Headers(t *testing.T) {
 		t.Fatalf("ToCheckerEndpoints() error = %v", err)
 	}
 
-	if endpoints[0].Headers["Authorization"] != "Bearer token123" {
-		t.Errorf("Authorization = %q, want %q", endpoints[0].Headers["Authorization"], "Bearer token123")
+	if endpoints[0].Headers["Authorization"] != "Bearer token123" {
+		t.Errorf("Authorization = %q, want %q", endpoints[0].Headers["Authorization"], "Bearer token123")
+	}
+	if endpoints[0].Headers["X-Custom"] != "value" {
+		t.Errorf("X-Custom = %q, want %q", endpoints[0].Headers["X-Custom"], "value")
+	}
+}
+
+// TestToCheckerEndpoints_HeaderAssert tests asserted header names are canonicalized and values expanded
+func TestToCheckerEndpoints_HeaderAssert(t *testing.T) {
+	t.Setenv("HC_TEST_CACHE", "HIT")
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{
+				URL:          "https://example.com",
+				HeaderAssert: map[string]string{"x-cache": "${HC_TEST_CACHE}", "via": ""},
+			},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	want := map[string]string{"X-Cache": "HIT", "Via": ""}
+	if !reflect.DeepEqual(endpoints[0].HeaderAssert, want) {
+		t.Errorf("HeaderAssert = %v, want %v", endpoints[0].HeaderAssert, want)
+	}
+}
+
+// TestValidateConfig_HeaderAssert tests asserted header names must be valid
+func TestValidateConfig_HeaderAssert(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Edge", URL: "https://a.example.com", HeaderAssert: map[string]string{"x-cache": "HIT"}},
+			{Name: "Bad", URL: "https://b.example.com", HeaderAssert: map[string]string{"x cache": "HIT"}},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "'Bad': invalid header_assert name 'x cache'") {
+		t.Errorf("errors = %v, want one header_assert error for Bad", errors)
+	}
+}
+
+// TestToCheckerEndpoints_Tags tests tag conversion
+func TestToCheckerEndpoints_Tags(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{URL: "https://api.example.com", Tags: []string{"critical", "api"}},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(endpoints[0].Tags, []string{"critical", "api"}) {
+		t.Errorf("Tags = %v, want [critical api]", endpoints[0].Tags)
+	}
+}
+
+// TestToCheckerEndpoints_Probes tests fanning out named probes
+func TestToCheckerEndpoints_Probes(t *testing.T) {
+	retries := 3
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{
+				Name:    "svc",
+				URL:     "https://svc.example.com/base/",
+				Retries: &retries,
+				Probes: map[string]string{
+					"ready": "/readyz",
+					"live":  "/livez",
+					"deps":  "deps/health",
+				},
+			},
+			{Name: "plain", URL: "https://plain.example.com"},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	expected := []struct{ name, url string }{
+		{"svc/deps", "https://svc.example.com/base/deps/health"},
+		{"svc/live", "https://svc.example.com/livez"},
+		{"svc/ready", "https://svc.example.com/readyz"},
+		{"plain", "https://plain.example.com"},
+	}
+
+	if len(endpoints) != len(expected) {
+		t.Fatalf("len(endpoints) = %d, want %d", len(endpoints), len(expected))
+	}
+	for i, want := range expected {
+		if endpoints[i].Name != want.name || endpoints[i].URL != want.url {
+			t.Errorf("endpoints[%d] = %s %s, want %s %s", i, endpoints[i].Name, endpoints[i].URL, want.name, want.url)
+		}
+	}
+	if endpoints[0].Retries != 3 {
+		t.Errorf("probe Retries = %d, want 3 (inherited)", endpoints[0].Retries)
+	}
+}
+
+// TestValidateConfig_EmptyProbePath tests empty probe path validation
+func TestValidateConfig_EmptyProbePath(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "svc", URL: "https://svc.example.com", Probes: map[string]string{"live": ""}},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+
+	if len(errors) != 1 || !strings.Contains(errors[0], "probe 'live'") {
+		t.Errorf("errors = %v, want empty probe path error", errors)
+	}
+}
+
+// TestToCheckerEndpoints_ExpectedBodyFile tests loading golden files relative to the config
+func TestToCheckerEndpoints_ExpectedBodyFile(t *testing.T) {
+	content := `
+endpoints:
+  - name: "Version"
+    url: "https://api.example.com/version"
+    expected_body_file: golden.json
+    normalize_whitespace: true
+`
+	cfgPath := createTempFile(t, "config.yaml", content)
+	golden := `{"version": "1.2.3"}`
+	if err := os.WriteFile(filepath.Join(filepath.Dir(cfgPath), "golden.json"), []byte(golden), 0644); err != nil {
+		t.Fatalf("failed to write golden file: %v", err)
+	}
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if errs := ValidateConfig(cfg); len(errs) != 0 {
+		t.Errorf("ValidateConfig() = %v, want no errors", errs)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if ep.ExpectedBody == nil || *ep.ExpectedBody != golden {
+		t.Errorf("ExpectedBody = %v, want %q", ep.ExpectedBody, golden)
+	}
+	if !ep.NormalizeWhitespace {
+		t.Error("NormalizeWhitespace = false, want true")
+	}
+}
+
+// TestValidateConfig_MissingBodyFile tests missing golden file validation
+func TestValidateConfig_MissingBodyFile(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Version", URL: "https://api.example.com", ExpectedBodyFile: "/nonexistent/golden.json"},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "expected_body_file") {
+		t.Errorf("errors = %v, want missing expected_body_file error", errors)
+	}
+
+	if _, err := cfg.ToCheckerEndpoints(); err == nil {
+		t.Error("ToCheckerEndpoints() error = nil, want error")
+	}
+}
+
+// TestToCheckerEndpoints_BodyFile tests loading request payloads with environment expansion
+func TestToCheckerEndpoints_BodyFile(t *testing.T) {
+	content := `
+endpoints:
+  - name: "Search"
+    url: "https://search.example.com/query"
+    method: post
+    body_file: payload.json
+  - name: "Ping"
+    url: "https://search.example.com/ping"
+    body: "ping ${SEARCH_ENV}"
+`
+	cfgPath := createTempFile(t, "config.yaml", content)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(cfgPath), "payload.json"), []byte(`{"env": "${SEARCH_ENV}"}`), 0644); err != nil {
+		t.Fatalf("failed to write payload file: %v", err)
+	}
+	t.Setenv("SEARCH_ENV", "staging")
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if errs := ValidateConfig(cfg); len(errs) != 0 {
+		t.Errorf("ValidateConfig() = %v, want no errors", errs)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if want := `{"env": "staging"}`; endpoints[0].Body != want {
+		t.Errorf("Body = %q, want %q", endpoints[0].Body, want)
+	}
+	if want := "ping staging"; endpoints[1].Body != want {
+		t.Errorf("Body = %q, want %q", endpoints[1].Body, want)
+	}
+
+	cfg.Endpoints[0].BodyFile = "missing.json"
+	if _, err := cfg.ToCheckerEndpoints(); err == nil || !strings.Contains(err.Error(), "endpoint 'Search'") || !strings.Contains(err.Error(), "body_file 'missing.json'") {
+		t.Errorf("ToCheckerEndpoints() error = %v, want missing body_file error naming the endpoint", err)
+	}
+
+	cfg.Endpoints[0].Body = "inline"
+	errs := ValidateConfig(cfg)
+	if len(errs) != 2 || !strings.Contains(errs[0], "body_file 'missing.json' not found") || !strings.Contains(errs[1], "mutually exclusive") {
+		t.Errorf("ValidateConfig() = %v, want missing file and mutually exclusive errors", errs)
+	}
+}
+
+// TestToCheckerEndpoints_ExpectedLocation tests redirect location conversion
+func TestToCheckerEndpoints_ExpectedLocation(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{URL: "http://example.com", ExpectedLocation: "https://example.com/", ExpectedLocationRegex: "^https://"},
+			{URL: "http://bad.example.com", ExpectedLocationRegex: "("},
+		},
+	}
+
+	if errs := ValidateConfig(cfg); len(errs) != 1 || !strings.Contains(errs[0], "expected_location_regex") {
+		t.Errorf("ValidateConfig() = %v, want invalid expected_location_regex error", errs)
+	}
+
+	if _, err := cfg.ToCheckerEndpoints(); err == nil {
+		t.Error("ToCheckerEndpoints() error = nil, want regex error")
+	}
+
+	cfg.Endpoints = cfg.Endpoints[:1]
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if endpoints[0].ExpectedLocation != "https://example.com/" {
+		t.Errorf("ExpectedLocation = %q, want %q", endpoints[0].ExpectedLocation, "https://example.com/")
+	}
+	if endpoints[0].LocationPattern == nil || endpoints[0].LocationPattern.String() != "^https://" {
+		t.Errorf("LocationPattern = %v, want ^https://", endpoints[0].LocationPattern)
+	}
+}
+
+// TestToCheckerEndpoints_BodyRegex tests body pattern compilation and validation
+func TestToCheckerEndpoints_BodyRegex(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{URL: "https://status.example.com", BodyRegex: `version: v\d+`},
+			{URL: "https://bad.example.com", BodyRegex: "[a-"},
+		},
+	}
+
+	if errs := ValidateConfig(cfg); len(errs) != 1 || !strings.Contains(errs[0], "invalid body_regex") {
+		t.Errorf("ValidateConfig() = %v, want invalid body_regex error", errs)
+	}
+	if _, err := cfg.ToCheckerEndpoints(); err == nil {
+		t.Error("ToCheckerEndpoints() error = nil, want regex error")
+	}
+
+	cfg.Endpoints = cfg.Endpoints[:1]
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if endpoints[0].BodyRegex == nil || !endpoints[0].BodyRegex.MatchString("version: v2") {
+		t.Errorf("BodyRegex = %v, want compiled pattern", endpoints[0].BodyRegex)
+	}
+}
+
+// TestToCheckerEndpoints_JSONAssert tests json_assert loading and empty path validation
+func TestToCheckerEndpoints_JSONAssert(t *testing.T) {
+	content := `
+endpoints:
+  - name: "Orders"
+    url: "https://orders.example.com/health"
+    json_assert:
+      path: status
+      equals: ok
+`
+	cfg, err := Load(createTempFile(t, "config.yaml", content))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if errs := ValidateConfig(cfg); len(errs) != 0 {
+		t.Errorf("ValidateConfig() = %v, want no errors", errs)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if got := endpoints[0].JSONAssert; got == nil || *got != (checker.JSONAssert{Path: "status", Equals: "ok"}) {
+		t.Errorf("JSONAssert = %+v, want status == ok", got)
+	}
+
+	cfg.Endpoints[0].JSONAssert.Path = " "
+	if errs := ValidateConfig(cfg); len(errs) != 1 || !strings.Contains(errs[0], "json_assert requires a path") {
+		t.Errorf("ValidateConfig() = %v, want empty path error", errs)
+	}
+}
+
+// TestToCheckerEndpoints_ExpectedStatusList tests expected_status as a code, class, range or list
+func TestToCheckerEndpoints_ExpectedStatusList(t *testing.T) {
+	content := `
+endpoints:
+  - name: "Queue"
+    url: "https://queue.example.com/health"
+    expected_status: [200, 204]
+  - name: "API"
+    url: "https://api.example.com/health"
+    expected_status: 201
+  - name: "Web"
+    url: "https://www.example.com"
+    expected_status: 2xx
+  - name: "Legacy"
+    url: "https://legacy.example.com"
+    expected_status: [304, "200-299"]
+`
+	cfg, err := Load(createTempFile(t, "config.yaml", content))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if errs := ValidateConfig(cfg); len(errs) != 0 {
+		t.Errorf("ValidateConfig() = %v, want no errors", errs)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if !reflect.DeepEqual(endpoints[0].ExpectedStatuses, []int{200, 204}) {
+		t.Errorf("ExpectedStatuses = %v, want [200 204]", endpoints[0].ExpectedStatuses)
+	}
+	if endpoints[1].ExpectedStatus != 201 || endpoints[1].ExpectedStatuses != nil {
+		t.Errorf("endpoints[1] ExpectedStatus = %d ExpectedStatuses = %v, want 201 and no set", endpoints[1].ExpectedStatus, endpoints[1].ExpectedStatuses)
+	}
+
+	if endpoints[2].ExpectedStatus != 0 || !reflect.DeepEqual(endpoints[2].ExpectedRanges, []checker.StatusRange{{Min: 200, Max: 299}}) {
+		t.Errorf("endpoints[2] ExpectedStatus = %d ExpectedRanges = %v, want 0 and [2xx]", endpoints[2].ExpectedStatus, endpoints[2].ExpectedRanges)
+	}
+	if !reflect.DeepEqual(endpoints[3].ExpectedStatuses, []int{304}) || !reflect.DeepEqual(endpoints[3].ExpectedRanges, []checker.StatusRange{{Min: 200, Max: 299}}) {
+		t.Errorf("endpoints[3] ExpectedStatuses = %v ExpectedRanges = %v, want [304] and [2xx]", endpoints[3].ExpectedStatuses, endpoints[3].ExpectedRanges)
+	}
+
+	cfg.Endpoints = cfg.Endpoints[:1]
+	for _, tt := range []struct {
+		status  []string
+		wantErr string
+	}{
+		{[]string{"200", "999"}, "expected_status must be between 100 and 599"},
+		{[]string{"2xz"}, "invalid status '2xz'"},
+		{[]string{"500-200"}, "invalid status range '500-200'"},
+		{[]string{"9xx"}, "expected_status must be between 100 and 599"},
+	} {
+		cfg.Endpoints[0].ExpectedStatus = tt.status
+		if errs := ValidateConfig(cfg); len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+			t.Errorf("ValidateConfig(%v) = %v, want %q", tt.status, errs, tt.wantErr)
+		}
+	}
+}
+
+// TestToCheckerEndpoints_ForbiddenStatus tests forbidden status conversion
+func TestToCheckerEndpoints_ForbiddenStatus(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{URL: "https://a.example.com", ForbiddenStatus: []int{500, 502}},
+			{URL: "https://b.example.com", ForbiddenStatus: []int{500}, ExpectedStatus: []string{"204"}},
+			{URL: "https://c.example.com"},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	if endpoints[0].ExpectedStatus != 0 {
+		t.Errorf("endpoints[0].ExpectedStatus = %d, want 0 (any)", endpoints[0].ExpectedStatus)
+	}
+	if !reflect.DeepEqual(endpoints[0].ForbiddenStatus, []int{500, 502}) {
+		t.Errorf("endpoints[0].ForbiddenStatus = %v, want [500 502]", endpoints[0].ForbiddenStatus)
+	}
+	if endpoints[1].ExpectedStatus != 204 {
+		t.Errorf("endpoints[1].ExpectedStatus = %d, want 204", endpoints[1].ExpectedStatus)
+	}
+	if endpoints[2].ExpectedStatus != 200 {
+		t.Errorf("endpoints[2].ExpectedStatus = %d, want 200", endpoints[2].ExpectedStatus)
+	}
+}
+
+// TestToCheckerEndpoints_StatusByMethod tests per-method status keys are canonicalized
+func TestToCheckerEndpoints_StatusByMethod(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{URL: "https://a.example.com", StatusByMethod: map[string]int{"head": 200, "options": 204}},
+			{URL: "https://b.example.com"},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+
+	want := map[string]int{"HEAD": 200, "OPTIONS": 204}
+	if !reflect.DeepEqual(endpoints[0].StatusByMethod, want) {
+		t.Errorf("endpoints[0].StatusByMethod = %v, want %v", endpoints[0].StatusByMethod, want)
+	}
+	if endpoints[1].StatusByMethod != nil {
+		t.Errorf("endpoints[1].StatusByMethod = %v, want nil", endpoints[1].StatusByMethod)
+	}
+
+	cfg.Endpoints[0].StatusByMethod = map[string]int{"get/x": 200, "options": 99}
+	errors := ValidateConfig(cfg)
+	if len(errors) != 2 {
+		t.Errorf("errors = %v, want invalid method and range errors", errors)
+	}
+}
+
+// TestConfig_Method tests request method conversion and validation
+func TestConfig_Method(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Ready", URL: "https://a.example.com/ready", Method: "head"},
+			{Name: "Default", URL: "https://b.example.com"},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if endpoints[0].Method != "HEAD" {
+		t.Errorf("endpoints[0].Method = %q, want HEAD", endpoints[0].Method)
+	}
+	if endpoints[1].Method != "" {
+		t.Errorf("endpoints[1].Method = %q, want empty (GET)", endpoints[1].Method)
+	}
+	if errors := ValidateConfig(cfg); len(errors) != 0 {
+		t.Errorf("errors = %v, want none", errors)
+	}
+
+	cfg.Endpoints[1].Method = "FETCH"
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "invalid method 'FETCH'") {
+		t.Errorf("errors = %v, want invalid method error", errors)
+	}
+
+	// Body assertions can't pass on HEAD responses
+	cfg.Endpoints[1].Method = ""
+	cfg.Endpoints[0].MinBodySize = 10
+	result := ValidateConfigWithWarnings(cfg)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "method HEAD returns no body") {
+		t.Errorf("warnings = %v, want HEAD body warning", result.Warnings)
+	}
+}
+
+// TestValidateConfig_Auth tests authentication settings validation
+func TestValidateConfig_Auth(t *testing.T) {
+	tests := []struct {
+		name        string
+		ep          Endpoint
+		errContains string
+	}{
+		{"digest", Endpoint{AuthType: "digest", Username: "u", Password: "p"}, ""},
+		{"basic without password", Endpoint{AuthType: "basic", Username: "u"}, ""},
+		{"unknown type", Endpoint{AuthType: "ntlm", Username: "u"}, "invalid auth_type 'ntlm'"},
+		{"missing username", Endpoint{AuthType: "digest", Password: "p"}, "requires username"},
+		{"missing type", Endpoint{Username: "u", Password: "p"}, "require auth_type"},
+		{"awsv4", Endpoint{AuthType: "awsv4", AWSRegion: "us-east-1", AWSService: "execute-api"}, ""},
+		{"awsv4 missing service", Endpoint{AuthType: "awsv4", AWSRegion: "us-east-1"}, "requires aws_region and aws_service"},
+		{"aws region without awsv4", Endpoint{AWSRegion: "us-east-1"}, "require auth_type 'awsv4'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ep.Name = "Appliance"
+			tt.ep.URL = "https://appliance.example.com"
+			errors := ValidateConfig(&Config{Endpoints: []Endpoint{tt.ep}})
+			if tt.errContains == "" {
+				if len(errors) != 0 {
+					t.Errorf("errors = %v, want none", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.errContains) {
+				t.Errorf("errors = %v, want %q", errors, tt.errContains)
+			}
+		})
+	}
+}
+
+// TestValidateConfig_InvalidForbiddenStatus tests forbidden status range validation
+func TestValidateConfig_InvalidForbiddenStatus(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "API", URL: "https://api.example.com", ForbiddenStatus: []int{500, 999}},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "forbidden_status") {
+		t.Errorf("errors = %v, want forbidden_status range error", errors)
+	}
+}
+
+// TestValidateConfig_Schedule tests cron schedule validation
+func TestValidateConfig_Schedule(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Cheap", URL: "https://a.example.com", Schedule: "*/5 * * * *"},
+			{Name: "Broken", URL: "https://b.example.com", Schedule: "*/5 * * *"},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "Broken") || !strings.Contains(errors[0], "invalid cron expression") {
+		t.Errorf("errors = %v, want one cron error for Broken", errors)
+	}
+
+	cfg.Endpoints = cfg.Endpoints[:1]
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if endpoints[0].Schedule != "*/5 * * * *" {
+		t.Errorf("Schedule = %q, want %q", endpoints[0].Schedule, "*/5 * * * *")
+	}
+}
+
+// TestValidateConfig_ExpectedProto tests protocol version validation
+func TestValidateConfig_ExpectedProto(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Edge", URL: "https://a.example.com", ExpectedProto: "HTTP/2.0"},
+			{Name: "Bad", URL: "https://b.example.com", ExpectedProto: "h2"},
+		},
 	}
-	if endpoints[0].Headers["X-Custom"] != "value" {
-		t.Errorf("X-Custom = %q, want %q", endpoints[0].Headers["X-Custom"], "value")
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "invalid expected_proto 'h2'") {
+		t.Errorf("errors = %v, want one expected_proto error", errors)
 	}
 }
 
@@ -459,10 +1163,9 @@ func TestValidateConfig_InvalidTimeout(t *testing.T) {
 
 // TestValidateConfig_InvalidStatusCode tests invalid status code
 func TestValidateConfig_InvalidStatusCode(t *testing.T) {
-	invalidStatus := 999
 	cfg := &Config{
 		Endpoints: []Endpoint{
-			{Name: "Test", URL: "https://example.com", ExpectedStatus: &invalidStatus},
+			{Name: "Test", URL: "https://example.com", ExpectedStatus: []string{"999"}},
 		},
 	}
 
@@ -479,6 +1182,60 @@ func TestValidateConfig_InvalidStatusCode(t *testing.T) {
 	}
 }
 
+// TestValidateConfig_InvalidContentType tests invalid require_content_type validation
+func TestValidateConfig_InvalidContentType(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Valid", URL: "https://a.example.com", RequireContentType: "application/json"},
+			{Name: "Invalid", URL: "https://b.example.com", RequireContentType: "application/"},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+
+	if len(errors) != 1 {
+		t.Fatalf("len(errors) = %d, want 1: %v", len(errors), errors)
+	}
+	if !strings.Contains(errors[0], "'Invalid'") || !strings.Contains(errors[0], "require_content_type") {
+		t.Errorf("error = %q, want require_content_type error for 'Invalid'", errors[0])
+	}
+}
+
+// TestValidateConfig_Accept tests accept shorthand validation
+func TestValidateConfig_Accept(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		wantErr  string
+	}{
+		{"media type", Endpoint{Accept: "application/vnd.api+json"}, ""},
+		{"with parameters", Endpoint{Accept: "application/vnd.api+json; version=2"}, ""},
+		{"malformed", Endpoint{Accept: "application/"}, "invalid accept"},
+		{"list", Endpoint{Accept: "application/json, text/plain"}, "invalid accept"},
+		{"wildcard", Endpoint{Accept: "application/*"}, "must not be a wildcard"},
+		{"header conflict", Endpoint{Accept: "application/json", Headers: map[string]string{"accept": "text/plain"}}, "cannot be combined with an Accept header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := tt.endpoint
+			ep.Name = "API"
+			ep.URL = "https://api.example.com"
+			errors := ValidateConfig(&Config{Endpoints: []Endpoint{ep}})
+
+			if tt.wantErr == "" {
+				if len(errors) != 0 {
+					t.Errorf("errors = %v, want none", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.wantErr) {
+				t.Errorf("errors = %v, want one containing %q", errors, tt.wantErr)
+			}
+		})
+	}
+}
+
 // TestValidateConfig_InvalidDefaultTimeout tests invalid default timeout
 func TestValidateConfig_InvalidDefaultTimeout(t *testing.T) {
 	cfg := &Config{
@@ -578,6 +1335,33 @@ func TestGenerateSampleConfig_Full(t *testing.T) {
 	}
 }
 
+// TestGenerateSampleConfig_Validates tests that both sample configs pass validation
+// with unique endpoint names
+func TestGenerateSampleConfig_Validates(t *testing.T) {
+	for _, full := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "endpoints.yaml")
+		if err := os.WriteFile(path, []byte(GenerateSampleConfig(full)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(full=%v) error = %v", full, err)
+		}
+
+		if errs := ValidateConfig(cfg); len(errs) > 0 {
+			t.Errorf("ValidateConfig(full=%v) errors = %v", full, errs)
+		}
+
+		names := make(map[string]bool)
+		for _, ep := range cfg.Endpoints {
+			if names[ep.Name] {
+				t.Errorf("sample config (full=%v) has duplicate endpoint name %q", full, ep.Name)
+			}
+			names[ep.Name] = true
+		}
+	}
+}
+
 // TestToCheckerEndpoints_EnvVarInHeaders tests environment variables in headers
 func TestToCheckerEndpoints_EnvVarInHeaders(t *testing.T) {
 	t.Setenv("AUTH_TOKEN", "secret-token-123")
@@ -710,6 +1494,50 @@ func TestValidateConfigWithWarnings_EnvVarSet(t *testing.T) {
 	}
 }
 
+// TestValidateConfigWithWarnings_Insecure tests warnings for disabled certificate verification
+func TestValidateConfigWithWarnings_Insecure(t *testing.T) {
+	insecure := true
+	secure := false
+
+	tests := []struct {
+		name            string
+		defaultInsecure bool
+		epInsecure      *bool
+		wantWarning     bool
+	}{
+		{"secure by default", false, nil, false},
+		{"endpoint insecure", false, &insecure, true},
+		{"insecure default", true, nil, true},
+		{"endpoint opts out of insecure default", true, &secure, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Defaults: Defaults{Insecure: tt.defaultInsecure},
+				Endpoints: []Endpoint{
+					{Name: "API", URL: "https://api.example.com", Insecure: tt.epInsecure},
+				},
+			}
+
+			result := ValidateConfigWithWarnings(cfg)
+
+			found := false
+			for _, w := range result.Warnings {
+				if strings.Contains(w, "insecure is enabled") {
+					found = true
+				}
+			}
+			if found != tt.wantWarning {
+				t.Errorf("insecure warning = %v, want %v (warnings: %v)", found, tt.wantWarning, result.Warnings)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("Errors = %v, want none", result.Errors)
+			}
+		})
+	}
+}
+
 // TestFindEnvVars tests finding environment variables
 func TestFindEnvVars(t *testing.T) {
 	tests := []struct {
@@ -737,6 +1565,85 @@ func TestFindEnvVars(t *testing.T) {
 	}
 }
 
+// TestFormatFromPath tests config format detection from file extension
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"endpoints.yaml", FormatYAML},
+		{"endpoints.yml", FormatYAML},
+		{"endpoints.json", FormatJSON},
+		{"ENDPOINTS.TOML", FormatTOML},
+		{"endpoints", FormatYAML},
+	}
+
+	for _, tt := range tests {
+		if got := FormatFromPath(tt.path); got != tt.expected {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+// TestMarshal_RoundTrip tests converting config to each format and loading it back
+func TestMarshal_RoundTrip(t *testing.T) {
+	content := `
+defaults:
+  timeout: 10s
+  retries: 2
+  expected_status: 204
+  follow_redirects: false
+  insecure: true
+
+endpoints:
+  - name: "Test API"
+    url: "https://api.example.com/health"
+    retries: 0
+    insecure: false
+    headers:
+      Authorization: "Bearer ${TOKEN}"
+      X-Request-ID: "healthcheck"
+  - url: "https://www.example.com"
+    timeout: 3s
+    expected_status: 301
+`
+	original, err := Load(createTempFile(t, "config.yaml", content))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, format := range SupportedFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := Marshal(original, format)
+			if err != nil {
+				t.Fatalf("Marshal(%q) error = %v", format, err)
+			}
+
+			converted, err := Load(createTempFile(t, "config."+format, string(data)))
+			if err != nil {
+				t.Fatalf("Load() converted error = %v\n%s", err, data)
+			}
+
+			// baseDir differs by design since each file lives in its own temp dir
+			converted.baseDir = original.baseDir
+			if !reflect.DeepEqual(original, converted) {
+				t.Errorf("round-trip mismatch:\noriginal  = %+v\nconverted = %+v", original, converted)
+			}
+		})
+	}
+}
+
+// TestMarshal_UnsupportedFormat tests conversion to an unknown format
+func TestMarshal_UnsupportedFormat(t *testing.T) {
+	_, err := Marshal(&Config{}, "xml")
+	if err == nil {
+		t.Fatal("Marshal() error = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "unsupported format") {
+		t.Errorf("error = %q, want to contain 'unsupported format'", err.Error())
+	}
+}
+
 // createTempFile creates a temporary file
 func createTempFile(t *testing.T, pattern, content string) string {
 	t.Helper()
@@ -748,3 +1655,366 @@ func createTempFile(t *testing.T, pattern, content string) string {
 	}
 	return tmpFile
 }
+
+// TestApplyOverrides tests command line key=value overrides
+func TestApplyOverrides(t *testing.T) {
+	retries := 1
+	cfg := &Config{
+		Defaults: Defaults{Timeout: "5s"},
+		Endpoints: []Endpoint{
+			{Name: "API", URL: "https://api.example.com", Retries: &retries},
+			{Name: "Web", URL: "https://web.example.com"},
+		},
+		baseDir: "/etc/healthcheck",
+	}
+
+	err := cfg.ApplyOverrides([]string{
+		"endpoints[0].timeout=30s",
+		"endpoints[1].retries=3",
+		"endpoints[1].headers.X-Debug=1",
+		"endpoints[1].forbidden_status=500,502",
+		"defaults.insecure=true",
+	})
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+
+	if cfg.Endpoints[0].Timeout != "30s" {
+		t.Errorf("Endpoints[0].Timeout = %q, want %q", cfg.Endpoints[0].Timeout, "30s")
+	}
+	if cfg.Endpoints[0].Retries == nil || *cfg.Endpoints[0].Retries != 1 {
+		t.Errorf("Endpoints[0].Retries = %v, want 1 (unchanged)", cfg.Endpoints[0].Retries)
+	}
+	if cfg.Endpoints[1].Retries == nil || *cfg.Endpoints[1].Retries != 3 {
+		t.Errorf("Endpoints[1].Retries = %v, want 3", cfg.Endpoints[1].Retries)
+	}
+	if cfg.Endpoints[1].Headers["x-debug"] != "1" {
+		t.Errorf("Endpoints[1].Headers = %v, want x-debug=1", cfg.Endpoints[1].Headers)
+	}
+	if !reflect.DeepEqual(cfg.Endpoints[1].ForbiddenStatus, []int{500, 502}) {
+		t.Errorf("Endpoints[1].ForbiddenStatus = %v, want [500 502]", cfg.Endpoints[1].ForbiddenStatus)
+	}
+	if !cfg.Defaults.Insecure || cfg.Defaults.Timeout != "5s" {
+		t.Errorf("Defaults = %+v, want insecure with timeout 5s", cfg.Defaults)
+	}
+	if cfg.baseDir != "/etc/healthcheck" {
+		t.Errorf("baseDir = %q, want preserved", cfg.baseDir)
+	}
+}
+
+// TestApplyOverrides_Invalid tests rejected override expressions
+func TestApplyOverrides_Invalid(t *testing.T) {
+	tests := []struct {
+		override string
+		contains string
+	}{
+		{"endpoints[0].timeout", "expected path=value"},
+		{"endpoints[5].timeout=1s", "out of range"},
+		{"endpoints[0].tiemout=1s", "unknown key 'tiemout'"},
+		{"endpoints.timeout=1s", "must start with"},
+		{"defaults[0].timeout=1s", "must start with"},
+		{"endpoints[x].timeout=1s", "invalid index"},
+		{"endpoints[0].timeout.x=1s", "cannot be indexed further"},
+		{"endpoints[0].forbidden_status[0]=500", "cannot be indexed further"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.override, func(t *testing.T) {
+			cfg := &Config{Endpoints: []Endpoint{{URL: "https://api.example.com"}}}
+			err := cfg.ApplyOverrides([]string{tt.override})
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("ApplyOverrides(%q) error = %v, want to contain %q", tt.override, err, tt.contains)
+			}
+		})
+	}
+}
+
+// TestValidateConfigWithWarnings_ConnectOnly tests the warning for ignored response assertions
+func TestValidateConfigWithWarnings_ConnectOnly(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Plain", URL: "https://a.example.com", ConnectOnly: true},
+			{Name: "Asserting", URL: "https://b.example.com", ConnectOnly: true, ExpectedStatus: []string{"204"}},
+			{Name: "Checksum", URL: "https://c.example.com", ConnectOnly: true, ExpectedBodySHA256: strings.Repeat("ab", 32)},
+		},
+	}
+
+	result := ValidateConfigWithWarnings(cfg)
+	if len(result.Warnings) != 2 || !strings.Contains(result.Warnings[0], "connect_only") || !strings.Contains(result.Warnings[1], "connect_only") {
+		t.Errorf("Warnings = %v, want two connect_only warnings", result.Warnings)
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if !endpoints[0].ConnectOnly {
+		t.Error("endpoints[0].ConnectOnly = false, want true")
+	}
+}
+
+// TestValidateConfig_ExpectedBodySHA256 tests body checksum validation
+func TestValidateConfig_ExpectedBodySHA256(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Good", URL: "https://a.example.com", ExpectedBodySHA256: strings.Repeat("Ab", 32)},
+			{Name: "Short", URL: "https://b.example.com", ExpectedBodySHA256: "abc123"},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "expected_body_sha256") {
+		t.Errorf("errors = %v, want one expected_body_sha256 error", errors)
+	}
+}
+
+// TestValidateConfig_BodySize tests body size range validation
+func TestValidateConfig_BodySize(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Good", URL: "https://a.example.com", MinBodySize: 10, MaxBodySize: 1000},
+			{Name: "Inverted", URL: "https://b.example.com", MinBodySize: 100, MaxBodySize: 10},
+			{Name: "Huge", URL: "https://c.example.com", MaxBodySize: 2 << 20},
+			{Name: "Negative", URL: "https://d.example.com", MinBodySize: -1},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 3 {
+		t.Fatalf("errors = %v, want 3", errors)
+	}
+	for i, want := range []string{"min_body_size must not exceed", "must not exceed 1048576", "must not be negative"} {
+		if !strings.Contains(errors[i], want) {
+			t.Errorf("errors[%d] = %q, want to contain %q", i, errors[i], want)
+		}
+	}
+}
+
+// TestValidateConfig_ClientCertP12 tests the PKCS#12 bundle is decoded at load time
+func TestValidateConfig_ClientCertP12(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Missing", URL: "https://a.example.com", ClientCertP12: "/nonexistent/client.p12"},
+			{Name: "Garbage", URL: "https://b.example.com", ClientCertP12: createTempFile(t, "client.p12", "not a bundle")},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 2 {
+		t.Fatalf("errors = %v, want 2", errors)
+	}
+	if !strings.Contains(errors[0], "failed to read client certificate") {
+		t.Errorf("errors[0] = %q, want read failure", errors[0])
+	}
+	if !strings.Contains(errors[1], "invalid PKCS#12 bundle") {
+		t.Errorf("errors[1] = %q, want invalid bundle", errors[1])
+	}
+}
+
+// TestValidateConfig_ClientCert tests the PEM client certificate and key are checked together
+func TestValidateConfig_ClientCert(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "No Key", URL: "https://a.example.com", ClientCert: "client.crt"},
+			{Name: "Both", URL: "https://b.example.com", ClientCert: "client.crt", ClientKey: "client.key", ClientCertP12: "client.p12"},
+			{Name: "Missing", URL: "https://c.example.com", ClientCert: "/nonexistent/client.crt", ClientKey: "/nonexistent/client.key"},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 4 {
+		t.Fatalf("errors = %v, want 4", errors)
+	}
+	if !strings.Contains(errors[0], "client_cert and client_key must be set together") {
+		t.Errorf("errors[0] = %q, want pair error", errors[0])
+	}
+	if !strings.Contains(errors[2], "cannot be combined with client_cert_p12") {
+		t.Errorf("errors[2] = %q, want conflict error", errors[2])
+	}
+	if !strings.Contains(errors[3], "client_cert: failed to load client certificate") {
+		t.Errorf("errors[3] = %q, want load failure", errors[3])
+	}
+}
+
+// TestValidateConfig_Proxy tests proxy URLs are parsed at validation time
+func TestValidateConfig_Proxy(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "HTTP", URL: "https://a.example.com", Proxy: "http://proxy.example.com:3128"},
+			{Name: "HTTPS", URL: "https://b.example.com", Proxy: "https://proxy.example.com:8443"},
+			{Name: "SOCKS", URL: "https://b.example.com", Proxy: "socks5://127.0.0.1:1080"},
+			{Name: "Scheme", URL: "https://c.example.com", Proxy: "ftp://proxy.example.com"},
+			{Name: "No Host", URL: "https://d.example.com", Proxy: "proxy.example.com:3128"},
+			{Name: "Direct", URL: "https://e.example.com", NoProxy: true},
+			{Name: "Both", URL: "https://f.example.com", Proxy: "http://proxy.example.com:3128", NoProxy: true},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 3 {
+		t.Fatalf("errors = %v, want 3", errors)
+	}
+	if !strings.Contains(errors[2], "Both") || !strings.Contains(errors[2], "cannot be combined with no_proxy") {
+		t.Errorf("errors[2] = %q, want conflict error", errors[2])
+	}
+	if !strings.Contains(errors[0], "Scheme") || !strings.Contains(errors[0], "scheme must be") {
+		t.Errorf("errors[0] = %q, want scheme error", errors[0])
+	}
+	if !strings.Contains(errors[1], "No Host") || !strings.Contains(errors[1], "proxy:") {
+		t.Errorf("errors[1] = %q, want proxy error", errors[1])
+	}
+}
+
+// TestValidateConfig_Resolve tests dial overrides are parsed at validation time
+func TestValidateConfig_Resolve(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Node", URL: "https://a.example.com", Resolve: []string{"a.example.com:443:10.0.0.1"}},
+			{Name: "Bad", URL: "https://b.example.com", Resolve: []string{"b.example.com:10.0.0.1"}},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 1 || !strings.Contains(errors[0], "'Bad': resolve: invalid resolve") {
+		t.Errorf("errors = %v, want one resolve error for Bad", errors)
+	}
+}
+
+// TestValidateConfig_MaxRedirects tests the redirect limit must be non-negative and needs redirects followed
+func TestValidateConfig_MaxRedirects(t *testing.T) {
+	follow := false
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Limited", URL: "https://a.example.com", MaxRedirects: 5},
+			{Name: "Negative", URL: "https://b.example.com", MaxRedirects: -1},
+			{Name: "NoFollow", URL: "https://c.example.com", MaxRedirects: 5, FollowRedirects: &follow},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 2 {
+		t.Fatalf("errors = %v, want 2", errors)
+	}
+	if !strings.Contains(errors[0], "'Negative': max_redirects must not be negative") {
+		t.Errorf("errors[0] = %q, want negative error", errors[0])
+	}
+	if !strings.Contains(errors[1], "'NoFollow': max_redirects requires follow_redirects") {
+		t.Errorf("errors[1] = %q, want follow_redirects error", errors[1])
+	}
+}
+
+// TestValidateConfig_CACert tests the CA bundle is loaded at validation time
+func TestValidateConfig_CACert(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Missing", URL: "https://a.example.com", CACert: "/nonexistent/ca.pem"},
+			{Name: "Garbage", URL: "https://b.example.com", CACert: createTempFile(t, "ca.pem", "not a certificate")},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	if len(errors) != 2 {
+		t.Fatalf("errors = %v, want 2", errors)
+	}
+	if !strings.Contains(errors[0], "ca_cert: failed to read CA certificate") {
+		t.Errorf("errors[0] = %q, want read failure", errors[0])
+	}
+	if !strings.Contains(errors[1], "ca_cert: no PEM certificates") {
+		t.Errorf("errors[1] = %q, want no certificates", errors[1])
+	}
+}
+
+// TestValidateConfig_Capture tests captured value names, paths and references
+func TestValidateConfig_Capture(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Login", URL: "https://a.example.com/login", Capture: map[string]string{"token": "data.token"}},
+			{Name: "Profile", URL: "{{base}}/me", Headers: map[string]string{"Authorization": "Bearer {{Token}}"}},
+			{Name: "Refresh", URL: "https://a.example.com/refresh?t={{token}}", Capture: map[string]string{"token": "token", "id": " "}},
+			{Name: "Self", URL: "https://a.example.com/{{self}}", Capture: map[string]string{"self": "id"}},
+		},
+	}
+
+	errors := ValidateConfig(cfg)
+	want := []string{
+		"endpoint 'Login': capture 'token' is also set by endpoint #3",
+		"endpoint 'Profile': uses {{base}} but no endpoint captures it",
+		"endpoint 'Refresh': capture 'id' requires a path",
+		"endpoint 'Refresh': uses {{token}} captured from its own response",
+		"endpoint 'Self': uses {{self}} captured from its own response",
+	}
+	if !reflect.DeepEqual(errors, want) {
+		t.Errorf("errors = %q, want %q", errors, want)
+	}
+}
+
+// TestLatencyBands tests degraded_latency and max_latency conversion and validation
+func TestLatencyBands(t *testing.T) {
+	cfg := &Config{
+		Endpoints: []Endpoint{
+			{Name: "Checkout", URL: "https://a.example.com", DegradedLatency: "300ms", MaxLatency: "1s"},
+		},
+	}
+
+	endpoints, err := cfg.ToCheckerEndpoints()
+	if err != nil {
+		t.Fatalf("ToCheckerEndpoints() error = %v", err)
+	}
+	if endpoints[0].DegradedLatency != 300*time.Millisecond || endpoints[0].MaxLatency != time.Second {
+		t.Errorf("bands = %v/%v, want 300ms/1s", endpoints[0].DegradedLatency, endpoints[0].MaxLatency)
+	}
+
+	cfg.Endpoints = append(cfg.Endpoints,
+		Endpoint{Name: "Inverted", URL: "https://b.example.com", DegradedLatency: "2s", MaxLatency: "1s"},
+		Endpoint{Name: "Broken", URL: "https://c.example.com", MaxLatency: "fast"},
+	)
+	errors := ValidateConfig(cfg)
+	if len(errors) != 2 || !strings.Contains(errors[0], "degraded_latency must be less than max_latency") ||
+		!strings.Contains(errors[1], "invalid max_latency format 'fast'") {
+		t.Errorf("errors = %v, want inverted band and format errors", errors)
+	}
+}
+
+// TestPromptConfig tests building a config from answers, asking again for invalid ones
+func TestPromptConfig(t *testing.T) {
+	answers := strings.Join([]string{
+		"ftp://api.example.com", // rejected scheme
+		"https://api.example.com/health",
+		"",    // default name
+		"abc", // rejected timeout
+		"10s",
+		"",
+		"https://web.example.com",
+		"Web",
+		"",
+		"2xx",
+		"", // finish
+	}, "\n") + "\n"
+
+	var prompts bytes.Buffer
+	cfg, err := PromptConfig(strings.NewReader(answers), &prompts)
+	if err != nil {
+		t.Fatalf("PromptConfig() error = %v", err)
+	}
+
+	want := []Endpoint{
+		{Name: "api.example.com", URL: "https://api.example.com/health", Timeout: "10s", ExpectedStatus: []string{"200"}},
+		{Name: "Web", URL: "https://web.example.com", Timeout: "5s", ExpectedStatus: []string{"2xx"}},
+	}
+	if !reflect.DeepEqual(cfg.Endpoints, want) {
+		t.Errorf("Endpoints = %+v, want %+v", cfg.Endpoints, want)
+	}
+	for _, msg := range []string{"! url must start with http:// or https://", "! invalid timeout format 'abc'"} {
+		if !strings.Contains(prompts.String(), msg) {
+			t.Errorf("prompts missing %q:\n%s", msg, prompts.String())
+		}
+	}
+	if errs := ValidateConfig(cfg); len(errs) > 0 {
+		t.Errorf("ValidateConfig() = %v, want valid", errs)
+	}
+
+	// Input ending before any endpoint is complete
+	if _, err := PromptConfig(strings.NewReader("https://api.example.com\n"), io.Discard); err == nil {
+		t.Error("PromptConfig() with no complete endpoint: error = nil, want error")
+	}
+}
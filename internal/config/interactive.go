@@ -0,0 +1,112 @@
+// Interactive config builder
+// Prompts for endpoints one at a time, validating each answer as it's entered
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// PromptConfig builds a config by asking on w for each endpoint's URL, name, timeout and
+// expected status, reading answers line by line from r
+// An answer that fails validation is asked for again; a blank URL or end of input finishes
+func PromptConfig(r io.Reader, w io.Writer) (*Config, error) {
+	cfg := &Config{}
+	scanner := bufio.NewScanner(r)
+
+	// ask prompts until the answer, or def when blank, passes validate; false at end of input
+	ask := func(prompt, def string, validate func(string) []string) (string, bool) {
+		for {
+			if def != "" {
+				fmt.Fprintf(w, "  %s [%s]: ", prompt, def)
+			} else {
+				fmt.Fprintf(w, "  %s: ", prompt)
+			}
+			if !scanner.Scan() {
+				fmt.Fprintln(w)
+				return "", false
+			}
+			answer := strings.TrimSpace(scanner.Text())
+			if answer == "" {
+				answer = def
+			}
+			errs := validate(answer)
+			if len(errs) == 0 {
+				return answer, true
+			}
+			for _, e := range errs {
+				fmt.Fprintf(w, "  ! %s\n", e)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "Enter the endpoints to check. Leave the URL blank to finish.")
+	for {
+		fmt.Fprintf(w, "\nEndpoint %d\n", len(cfg.Endpoints)+1)
+
+		// Each field is validated together with the fields already accepted,
+		// so any new error belongs to the answer just given
+		var ep Endpoint
+		check := func(set func(*Endpoint, string)) func(string) []string {
+			return func(answer string) []string {
+				candidate := ep
+				set(&candidate, answer)
+				errs := ValidateConfig(&Config{Endpoints: []Endpoint{candidate}})
+
+				// Drop the endpoint prefix, the endpoint is the one being entered
+				prefix := "endpoint #1: "
+				if candidate.Name != "" {
+					prefix = fmt.Sprintf("endpoint '%s': ", candidate.Name)
+				}
+				for i, e := range errs {
+					errs[i] = strings.TrimPrefix(e, prefix)
+				}
+				return errs
+			}
+		}
+
+		rawURL, ok := ask("URL", "", func(answer string) []string {
+			if answer == "" {
+				return nil
+			}
+			return check(func(e *Endpoint, v string) { e.URL = v })(answer)
+		})
+		if !ok || rawURL == "" {
+			break
+		}
+		ep.URL = rawURL
+
+		if ep.Name, ok = ask("Name", defaultEndpointName(rawURL), check(func(e *Endpoint, v string) { e.Name = v })); !ok {
+			break
+		}
+		if ep.Timeout, ok = ask("Timeout", "5s", check(func(e *Endpoint, v string) { e.Timeout = v })); !ok {
+			break
+		}
+		status, ok := ask("Expected status", "200", check(func(e *Endpoint, v string) { e.ExpectedStatus = []string{v} }))
+		if !ok {
+			break
+		}
+		ep.ExpectedStatus = []string{status}
+
+		cfg.Endpoints = append(cfg.Endpoints, ep)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read answers: %w", err)
+	}
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints entered")
+	}
+
+	return cfg, nil
+}
+
+// defaultEndpointName suggests the URL host as an endpoint name
+func defaultEndpointName(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
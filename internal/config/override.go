@@ -0,0 +1,163 @@
+// Config overrides
+// Applies command line key=value overrides to a loaded config
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// pathSegment is one element of an override path such as "endpoints[0]"
+type pathSegment struct {
+	key   string
+	index int // -1 when the segment is not indexed
+}
+
+// ApplyOverrides applies overrides of the form "path=value" to the config
+// Paths use dotted config names with list indexes, e.g. "endpoints[0].timeout=30s"
+// or "defaults.retries=3"; values are converted like values from a config file
+func (c *Config) ApplyOverrides(overrides []string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	settings, err := toSettings(c)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range overrides {
+		path, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return fmt.Errorf("invalid override '%s': expected path=value", o)
+		}
+		segments, err := parseOverridePath(path)
+		if err != nil {
+			return fmt.Errorf("invalid override '%s': %w", o, err)
+		}
+		if err := setPath(settings, segments, value); err != nil {
+			return fmt.Errorf("invalid override '%s': %w", o, err)
+		}
+	}
+
+	// Decode through viper so values get the same conversions as a config file
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to apply overrides: %w", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("failed to apply overrides: %w", err)
+	}
+	cfg.baseDir = c.baseDir
+	*c = cfg
+
+	return nil
+}
+
+// parseOverridePath splits a path and checks it names a known config key
+func parseOverridePath(path string) ([]pathSegment, error) {
+	parts := strings.Split(path, ".")
+	segments := make([]pathSegment, 0, len(parts))
+	for _, part := range parts {
+		seg := pathSegment{key: part, index: -1}
+		if name, rest, ok := strings.Cut(part, "["); ok {
+			idx, err := strconv.Atoi(strings.TrimSuffix(rest, "]"))
+			if !strings.HasSuffix(rest, "]") || err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid index in '%s'", part)
+			}
+			seg = pathSegment{key: name, index: idx}
+		}
+		if seg.key == "" {
+			return nil, fmt.Errorf("empty key in path '%s'", path)
+		}
+		segments = append(segments, seg)
+	}
+
+	// First segment selects the section
+	var section reflect.Type
+	switch {
+	case segments[0].key == "defaults" && segments[0].index < 0:
+		section = reflect.TypeOf(Defaults{})
+	case segments[0].key == "endpoints" && segments[0].index >= 0:
+		section = reflect.TypeOf(Endpoint{})
+	default:
+		return nil, fmt.Errorf("path must start with 'defaults.' or 'endpoints[N].'")
+	}
+	if len(segments) < 2 {
+		return nil, fmt.Errorf("missing key after '%s'", parts[0])
+	}
+
+	// Second segment must be a known field; only maps take a further key
+	// Lists are replaced whole, e.g. "forbidden_status=500,502"
+	kind, ok := fieldKinds(section)[segments[1].key]
+	if !ok {
+		return nil, fmt.Errorf("unknown key '%s'", segments[1].key)
+	}
+	maxDepth := 2
+	if kind == reflect.Map {
+		maxDepth = 3
+	}
+	if len(segments) > maxDepth || segments[1].index >= 0 || (len(segments) == 3 && segments[2].index >= 0) {
+		return nil, fmt.Errorf("key '%s' cannot be indexed further", segments[1].key)
+	}
+
+	return segments, nil
+}
+
+// fieldKinds maps config key names of a struct to their kinds, dereferencing pointers
+func fieldKinds(t reflect.Type) map[string]reflect.Kind {
+	kinds := make(map[string]reflect.Kind, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		kinds[name] = ft.Kind()
+	}
+	return kinds
+}
+
+// setPath sets value at the path inside a settings map, creating intermediate maps
+func setPath(settings map[string]any, segments []pathSegment, value string) error {
+	current := settings
+	for i, seg := range segments {
+		last := i == len(segments)-1
+
+		if seg.index < 0 {
+			if last {
+				current[seg.key] = value
+				return nil
+			}
+			next, ok := current[seg.key].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				current[seg.key] = next
+			}
+			current = next
+			continue
+		}
+
+		// Only sections are indexed; parseOverridePath rejects indexed leaves
+		list, _ := current[seg.key].([]any)
+		if seg.index >= len(list) {
+			return fmt.Errorf("index %d out of range for '%s' (%d items)", seg.index, seg.key, len(list))
+		}
+		next, ok := list[seg.index].(map[string]any)
+		if !ok {
+			return fmt.Errorf("'%s[%d]' is not a section", seg.key, seg.index)
+		}
+		current = next
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// URL list parsing
+// Builds a config from plain "url [timeout] [status]" lines, e.g. piped on stdin
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// ParseURLList reads one endpoint per line as "url [timeout] [expected_status]"
+// Optional fields may appear in either order; blank lines and '#' comments are skipped
+func ParseURLList(r io.Reader) (*Config, error) {
+	cfg := &Config{}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		ep := Endpoint{Name: fields[0], URL: fields[0]}
+		for _, field := range fields[1:] {
+			if _, err := checker.ParseStatusRange(field); err == nil {
+				if len(ep.ExpectedStatus) > 0 {
+					return nil, fmt.Errorf("line %d: duplicate expected status '%s'", lineNum, field)
+				}
+				ep.ExpectedStatus = []string{field}
+				continue
+			}
+			if _, err := time.ParseDuration(field); err == nil {
+				if ep.Timeout != "" {
+					return nil, fmt.Errorf("line %d: duplicate timeout '%s'", lineNum, field)
+				}
+				ep.Timeout = field
+				continue
+			}
+			return nil, fmt.Errorf("line %d: '%s' is neither a timeout (e.g. 10s) nor a status code", lineNum, field)
+		}
+		cfg.Endpoints = append(cfg.Endpoints, ep)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+
+	return cfg, nil
+}
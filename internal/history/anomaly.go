@@ -0,0 +1,76 @@
+// Latency anomaly detection
+// Warns when a watch cycle's latency jumps well above the endpoint's recent p95
+package history
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// anomalyWindow is how many recent latencies per endpoint the rolling p95 covers
+const anomalyWindow = 100
+
+// AnomalyDetector warns when a response is slower than factor × the endpoint's rolling p95
+// Latencies are kept in memory only, in a bounded ring buffer per endpoint
+type AnomalyDetector struct {
+	factor  float64
+	windows map[string]*latencyRing // Keyed by endpointKey
+}
+
+// latencyRing holds the most recent anomalyWindow latencies of one endpoint
+type latencyRing struct {
+	samples []time.Duration
+	next    int // Index overwritten by the next sample once full
+}
+
+// NewAnomalyDetector creates a detector warning above factor × the rolling p95
+func NewAnomalyDetector(factor float64) *AnomalyDetector {
+	return &AnomalyDetector{factor: factor, windows: make(map[string]*latencyRing)}
+}
+
+// Apply adds a warning to each result slower than factor × its endpoint's p95 over previous cycles,
+// then records the latency; only results with a response are compared and recorded
+// No warnings are given until an endpoint has MinSamples latencies
+func (d *AnomalyDetector) Apply(batch *checker.BatchResult) {
+	for i := range batch.Results {
+		r := &batch.Results[i]
+		if r.StatusCode == nil {
+			continue
+		}
+
+		key := endpointKey(r)
+		ring, ok := d.windows[key]
+		if !ok {
+			ring = &latencyRing{}
+			d.windows[key] = ring
+		}
+		if len(ring.samples) >= MinSamples {
+			p95 := ring.percentile(95)
+			if p95 > 0 && float64(r.Latency) > d.factor*float64(p95) {
+				r.Warnings = append(r.Warnings, fmt.Sprintf("latency anomaly: %s is %.1fx the rolling p95 of %s",
+					r.Latency.Round(time.Millisecond), float64(r.Latency)/float64(p95), p95.Round(time.Millisecond)))
+			}
+		}
+		ring.add(r.Latency)
+	}
+}
+
+// add records a latency, replacing the oldest once the window is full
+func (r *latencyRing) add(latency time.Duration) {
+	if len(r.samples) < anomalyWindow {
+		r.samples = append(r.samples, latency)
+		return
+	}
+	r.samples[r.next] = latency
+	r.next = (r.next + 1) % anomalyWindow
+}
+
+// percentile returns the p-th percentile (0-100) of the window
+func (r *latencyRing) percentile(p float64) time.Duration {
+	sorted := slices.Clone(r.samples)
+	slices.Sort(sorted)
+	return sorted[nearestRank(len(sorted), p)-1]
+}
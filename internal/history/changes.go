@@ -0,0 +1,77 @@
+// Change detection
+// Persists response body hashes between runs to flag changed content
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// shortHashLen is the number of hash characters shown in change warnings
+const shortHashLen = 12
+
+// BodyState maps endpoint names to the SHA-256 of their last seen response body
+type BodyState map[string]string
+
+// LoadBodyState reads a change detection state file
+// A missing file is not an error and yields an empty state, so the first run sets the baseline
+func LoadBodyState(path string) (BodyState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return BodyState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state := BodyState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return state, nil
+}
+
+// Save writes the state file, replacing it atomically so an interrupted run keeps the old baseline
+func (s BodyState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// DetectChanges warns on results whose body hash differs from the state and records the new hashes
+// Results without a body hash, such as failed requests, keep their previous baseline
+func (s BodyState) DetectChanges(results []checker.Result) {
+	for i, r := range results {
+		if r.BodySHA256 == "" {
+			continue
+		}
+		if prev, ok := s[r.Name]; ok && prev != r.BodySHA256 {
+			results[i].Warnings = append(results[i].Warnings, fmt.Sprintf("changed: body differs from last run (sha256 %s → %s)",
+				prev[:min(shortHashLen, len(prev))], r.BodySHA256[:shortHashLen]))
+		}
+		s[r.Name] = r.BodySHA256
+	}
+}
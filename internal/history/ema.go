@@ -0,0 +1,41 @@
+// Latency moving average
+// Smooths each endpoint's latency across watch cycles into a stable typical value
+package history
+
+import (
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// EMATracker keeps an exponential moving average of each endpoint's latency
+// Higher alpha values follow new latencies faster; lower ones smooth more
+type EMATracker struct {
+	alpha      float64
+	byEndpoint map[string]time.Duration // Keyed by endpointKey
+}
+
+// NewEMATracker creates a tracker weighting each new latency by alpha (0 < alpha <= 1)
+func NewEMATracker(alpha float64) *EMATracker {
+	return &EMATracker{alpha: alpha, byEndpoint: make(map[string]time.Duration)}
+}
+
+// Apply folds each result's latency into its endpoint's average and sets LatencyEMA
+// Only results with a response are folded in; the first one starts the average
+// Results without a response keep the endpoint's current average, if any
+func (t *EMATracker) Apply(batch *checker.BatchResult) {
+	for i := range batch.Results {
+		r := &batch.Results[i]
+		key := endpointKey(r)
+		ema, ok := t.byEndpoint[key]
+		if r.StatusCode != nil {
+			if ok {
+				ema = time.Duration(t.alpha*float64(r.Latency) + (1-t.alpha)*float64(ema))
+			} else {
+				ema = r.Latency
+			}
+			t.byEndpoint[key] = ema
+		}
+		r.LatencyEMA = ema
+	}
+}
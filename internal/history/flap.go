@@ -0,0 +1,96 @@
+// Flap suppression
+// Holds each endpoint's reported health until a change persists across watch cycles
+package history
+
+import (
+	"fmt"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// FlapFilter reports an endpoint down only after downThreshold consecutive failed cycles,
+// and recovered only after upThreshold consecutive healthy ones
+type FlapFilter struct {
+	downThreshold int
+	upThreshold   int
+	states        map[string]*flapState // Keyed by name and URL, as names may repeat
+}
+
+// flapState is the reported health of one endpoint and the run of cycles disagreeing with it
+type flapState struct {
+	healthy  bool
+	streak   int
+	lastKind checker.ErrorKind // Kind of the failure that made the endpoint reported down
+}
+
+// NewFlapFilter creates a filter requiring down consecutive failed cycles to report an endpoint down
+// and up consecutive healthy cycles to report it recovered; an up of 0 uses down
+// A threshold of 1 or less reports that change as observed
+func NewFlapFilter(down, up int) *FlapFilter {
+	if up <= 0 {
+		up = down
+	}
+	return &FlapFilter{downThreshold: down, upThreshold: up, states: make(map[string]*flapState)}
+}
+
+// endpointKey identifies an endpoint across watch cycles by name and URL, as names may repeat
+// e.g. when several globbed configs use the same name
+func endpointKey(r *checker.Result) string {
+	return r.Name + "\x00" + r.URL
+}
+
+// Apply replaces each result's health with its reported health and adjusts the summary
+// Held-back changes are explained by a warning (pending failure) or error (pending recovery)
+// An endpoint's first result is reported as observed
+func (f *FlapFilter) Apply(batch *checker.BatchResult) {
+	for i := range batch.Results {
+		r := &batch.Results[i]
+
+		key := endpointKey(r)
+		state, ok := f.states[key]
+		if !ok {
+			f.states[key] = &flapState{healthy: r.Healthy, lastKind: r.ErrorKind}
+			continue
+		}
+		if r.Healthy == state.healthy {
+			state.streak = 0
+			if !r.Healthy {
+				state.lastKind = r.ErrorKind
+			}
+			continue
+		}
+
+		threshold := f.downThreshold
+		if !state.healthy {
+			threshold = f.upThreshold
+		}
+		state.streak++
+		if state.streak >= threshold {
+			state.healthy = r.Healthy
+			state.streak = 0
+			state.lastKind = r.ErrorKind
+			continue
+		}
+
+		// Keep reporting the previous state until the change persists
+		if state.healthy {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("flapping: failed %d/%d cycles before reported down: %v", state.streak, threshold, r.Error))
+			r.Healthy = true
+			r.Degraded = false
+			r.Error = nil
+			r.ErrorKind = checker.KindNone
+			batch.Summary.Healthy++
+			batch.Summary.Unhealthy--
+		} else {
+			r.Healthy = false
+			r.Error = fmt.Errorf("recovering: healthy %d/%d cycles before reported up", state.streak, threshold)
+			r.ErrorKind = state.lastKind
+			if r.Degraded {
+				batch.Summary.Degraded--
+			}
+			r.Degraded = false
+			batch.Summary.Healthy--
+			batch.Summary.Unhealthy++
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Check history persistence
+// Records check results to a JSON Lines file and computes latency baselines
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// MinSamples is the minimum number of samples required to derive a baseline
+const MinSamples = 5
+
+// Record is a single persisted check result
+type Record struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	Healthy    bool      `json:"healthy"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+}
+
+// Load reads all records from a history file
+// A missing file is not an error and yields no records
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("invalid history record at line %d: %w", line, err)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Append appends batch results to a history file, creating it if needed
+func Append(path string, batch checker.BatchResult) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, r := range batch.Results {
+		record := Record{
+			Timestamp:  batch.Timestamp.UTC(),
+			Name:       r.Name,
+			URL:        r.URL,
+			Healthy:    r.Healthy,
+			StatusCode: r.StatusCode,
+			LatencyMs:  r.Latency.Milliseconds(),
+		}
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write history record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Percentile returns the p-th percentile (0-100) latency of responses recorded for an endpoint
+// Returns false if fewer than MinSamples responses were recorded
+func Percentile(records []Record, name string, p float64) (time.Duration, bool) {
+	latencies := make([]int64, 0)
+	for _, r := range records {
+		// Only samples that received a response carry meaningful latency
+		if r.Name == name && r.StatusCode != nil {
+			latencies = append(latencies, r.LatencyMs)
+		}
+	}
+
+	if len(latencies) < MinSamples {
+		return 0, false
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	return time.Duration(latencies[nearestRank(len(latencies), p)-1]) * time.Millisecond, true
+}
+
+// nearestRank returns the 1-based nearest-rank index of the p-th percentile among n sorted samples
+func nearestRank(n int, p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > n {
+		rank = n
+	}
+	return rank
+}
+
+// AdaptiveTimeout derives a timeout as factor × the endpoint's historical p99 latency
+// Returns fallback if there is not enough history, and never returns less than minTimeout
+func AdaptiveTimeout(records []Record, name string, factor float64, minTimeout, fallback time.Duration) time.Duration {
+	p99, ok := Percentile(records, name, 99)
+	if !ok {
+		return fallback
+	}
+
+	timeout := time.Duration(float64(p99) * factor)
+	if timeout < minTimeout {
+		timeout = minTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,383 @@
+// History module unit tests
+// Test history persistence and latency baselines
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// makeRecords creates response records with the given latencies for an endpoint
+func makeRecords(name string, latenciesMs ...int64) []Record {
+	status := 200
+	records := make([]Record, 0, len(latenciesMs))
+	for _, ms := range latenciesMs {
+		records = append(records, Record{Name: name, StatusCode: &status, LatencyMs: ms})
+	}
+	return records
+}
+
+// TestLoad_MissingFile tests loading a nonexistent history file
+func TestLoad_MissingFile(t *testing.T) {
+	records, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("len(records) = %d, want 0", len(records))
+	}
+}
+
+// TestAppendAndLoad tests round-tripping batch results through a history file
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	status := 200
+
+	batch := checker.BatchResult{
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Results: []checker.Result{
+			{Name: "API", URL: "https://api.example.com", Healthy: true, StatusCode: &status, Latency: 45 * time.Millisecond},
+			{Name: "DB", URL: "https://db.example.com", Healthy: false},
+		},
+	}
+
+	if err := Append(path, batch); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(path, batch); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	records, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("len(records) = %d, want 4", len(records))
+	}
+	if records[0].Name != "API" || records[0].LatencyMs != 45 {
+		t.Errorf("records[0] = %+v, want API with 45ms", records[0])
+	}
+	if records[1].StatusCode != nil {
+		t.Errorf("records[1].StatusCode = %v, want nil", records[1].StatusCode)
+	}
+}
+
+// TestLoad_InvalidRecord tests loading a corrupt history file
+func TestLoad_InvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte("not json\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
+
+// TestPercentile tests nearest-rank percentile calculation
+func TestPercentile(t *testing.T) {
+	records := makeRecords("API", 10, 20, 30, 40, 50, 60, 70, 80, 90, 100)
+	records = append(records, Record{Name: "API", LatencyMs: 5000}) // No response, ignored
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 100 * time.Millisecond},
+		{0, 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		got, ok := Percentile(records, "API", tt.p)
+		if !ok {
+			t.Fatalf("Percentile(%v) ok = false, want true", tt.p)
+		}
+		if got != tt.expected {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.expected)
+		}
+	}
+}
+
+// TestPercentile_NotEnoughSamples tests percentile with sparse history
+func TestPercentile_NotEnoughSamples(t *testing.T) {
+	records := makeRecords("API", 10, 20)
+
+	if _, ok := Percentile(records, "API", 99); ok {
+		t.Error("Percentile() ok = true, want false")
+	}
+	if _, ok := Percentile(records, "Other", 99); ok {
+		t.Error("Percentile() ok = true for unknown endpoint, want false")
+	}
+}
+
+// TestAdaptiveTimeout tests timeout derivation from history
+func TestAdaptiveTimeout(t *testing.T) {
+	records := makeRecords("slow", 900, 1000, 1100, 1200, 1300)
+	records = append(records, makeRecords("fast", 1, 1, 2, 2, 3)...)
+
+	tests := []struct {
+		name     string
+		endpoint string
+		expected time.Duration
+	}{
+		{"scaled p99", "slow", 3900 * time.Millisecond},
+		{"clamped to minimum", "fast", 100 * time.Millisecond},
+		{"fallback without history", "unknown", 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdaptiveTimeout(records, tt.endpoint, 3, 100*time.Millisecond, 5*time.Second)
+			if got != tt.expected {
+				t.Errorf("AdaptiveTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBodyState_DetectChanges tests change warnings across runs persisted through a state file
+func TestBodyState_DetectChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	hashA := strings.Repeat("a", 64)
+	hashB := strings.Repeat("b", 64)
+
+	// First run: no baseline, no warnings
+	state, err := LoadBodyState(path)
+	if err != nil {
+		t.Fatalf("LoadBodyState() error = %v", err)
+	}
+	results := []checker.Result{
+		{Name: "status-page", BodySHA256: hashA},
+		{Name: "version", BodySHA256: hashA},
+	}
+	state.DetectChanges(results)
+	for _, r := range results {
+		if len(r.Warnings) != 0 {
+			t.Errorf("%s Warnings = %v, want none on first run", r.Name, r.Warnings)
+		}
+	}
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Second run: one body changed, one request failed without a body
+	state, err = LoadBodyState(path)
+	if err != nil {
+		t.Fatalf("LoadBodyState() error = %v", err)
+	}
+	results = []checker.Result{
+		{Name: "status-page", BodySHA256: hashB},
+		{Name: "version"},
+	}
+	state.DetectChanges(results)
+	if len(results[0].Warnings) != 1 || !strings.HasPrefix(results[0].Warnings[0], "changed:") {
+		t.Errorf("status-page Warnings = %v, want one change warning", results[0].Warnings)
+	}
+	if len(results[1].Warnings) != 0 {
+		t.Errorf("version Warnings = %v, want none", results[1].Warnings)
+	}
+	if state["status-page"] != hashB || state["version"] != hashA {
+		t.Errorf("state = %v, want updated status-page and kept version baseline", state)
+	}
+}
+
+// TestLoadBodyState_Invalid tests loading a corrupt state file
+func TestLoadBodyState_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBodyState(path); err == nil {
+		t.Error("LoadBodyState() error = nil, want error")
+	}
+}
+
+// TestFlapFilter tests that state changes are reported only after the threshold of consecutive cycles
+func TestFlapFilter(t *testing.T) {
+	up := checker.Result{Name: "api", Healthy: true}
+	down := checker.Result{Name: "api", Error: errors.New("connection refused"), ErrorKind: checker.KindRefused}
+
+	cycles := []struct {
+		observed checker.Result
+		reported bool
+		note     string
+	}{
+		{up, true, ""},
+		{down, true, "flapping: failed 1/3 cycles"},
+		{up, true, ""},
+		{down, true, "flapping: failed 1/3 cycles"},
+		{down, true, "flapping: failed 2/3 cycles"},
+		{down, false, "connection refused"},
+		{up, false, "recovering: healthy 1/3 cycles"},
+		{up, false, "recovering: healthy 2/3 cycles"},
+		{up, true, ""},
+	}
+
+	f := NewFlapFilter(3, 0)
+	for i, c := range cycles {
+		batch := checker.BatchResult{Results: []checker.Result{c.observed}}
+		if c.observed.Healthy {
+			batch.Summary = checker.Summary{Total: 1, Healthy: 1}
+		} else {
+			batch.Summary = checker.Summary{Total: 1, Unhealthy: 1}
+		}
+		f.Apply(&batch)
+
+		r := batch.Results[0]
+		if r.Healthy != c.reported {
+			t.Errorf("cycle %d: Healthy = %v, want %v", i, r.Healthy, c.reported)
+		}
+		if c.reported && (batch.Summary.Healthy != 1 || batch.Summary.Unhealthy != 0) ||
+			!c.reported && (batch.Summary.Healthy != 0 || batch.Summary.Unhealthy != 1) {
+			t.Errorf("cycle %d: Summary = %+v, want it to match the reported state", i, batch.Summary)
+		}
+		note := strings.Join(r.Warnings, "; ")
+		if r.Error != nil {
+			note += r.Error.Error()
+		}
+		if c.note == "" && note != "" || !strings.Contains(note, c.note) {
+			t.Errorf("cycle %d: warnings/error = %q, want %q", i, note, c.note)
+		}
+		if !r.Healthy && r.ErrorKind != checker.KindRefused {
+			t.Errorf("cycle %d: ErrorKind = %q, want %q", i, r.ErrorKind, checker.KindRefused)
+		}
+	}
+}
+
+// TestFlapFilter_Thresholds tests separate down and recovery thresholds,
+// and that endpoints sharing a name keep separate state
+func TestFlapFilter_Thresholds(t *testing.T) {
+	f := NewFlapFilter(1, 2)
+	apply := func(results ...checker.Result) []bool {
+		batch := checker.BatchResult{Results: results}
+		f.Apply(&batch)
+		healthy := make([]bool, len(batch.Results))
+		for i, r := range batch.Results {
+			healthy[i] = r.Healthy
+		}
+		return healthy
+	}
+	up := func(url string) checker.Result { return checker.Result{Name: "api", URL: url, Healthy: true} }
+	down := func(url string) checker.Result {
+		return checker.Result{Name: "api", URL: url, Error: errors.New("connection refused"), ErrorKind: checker.KindRefused}
+	}
+
+	cycles := []struct {
+		observed []checker.Result
+		reported []bool
+	}{
+		{[]checker.Result{up("https://a"), down("https://b")}, []bool{true, false}},
+		{[]checker.Result{down("https://a"), up("https://b")}, []bool{false, false}}, // a down at once, b recovering 1/2
+		{[]checker.Result{up("https://a"), up("https://b")}, []bool{false, true}},    // a recovering 1/2, b recovered
+		{[]checker.Result{up("https://a"), up("https://b")}, []bool{true, true}},
+	}
+	for i, c := range cycles {
+		if got := apply(c.observed...); !slices.Equal(got, c.reported) {
+			t.Errorf("cycle %d: Healthy = %v, want %v", i, got, c.reported)
+		}
+	}
+}
+
+// TestAnomalyDetector tests warnings against the rolling p95 latency
+func TestAnomalyDetector(t *testing.T) {
+	status := 200
+	result := func(ms int) checker.Result {
+		return checker.Result{Name: "api", Healthy: true, StatusCode: &status, Latency: time.Duration(ms) * time.Millisecond}
+	}
+	d := NewAnomalyDetector(2)
+	apply := func(r checker.Result) []string {
+		batch := checker.BatchResult{Results: []checker.Result{r}}
+		d.Apply(&batch)
+		return batch.Results[0].Warnings
+	}
+
+	// No baseline yet
+	if w := apply(result(900)); len(w) != 0 {
+		t.Errorf("first result warnings = %v, want none", w)
+	}
+	for i := 0; i < MinSamples; i++ {
+		apply(result(100))
+	}
+
+	if w := apply(result(150)); len(w) != 0 {
+		t.Errorf("150ms warnings = %v, want none", w)
+	}
+	if w := apply(result(2000)); len(w) != 1 || w[0] != "latency anomaly: 2s is 2.2x the rolling p95 of 900ms" {
+		t.Errorf("2000ms warnings = %v, want anomaly against 900ms p95", w)
+	}
+
+	// Failed connections are neither compared nor recorded
+	if w := apply(checker.Result{Name: "api", Latency: time.Minute}); len(w) != 0 {
+		t.Errorf("no response warnings = %v, want none", w)
+	}
+
+	// The window is bounded, so old outliers age out
+	for i := 0; i < anomalyWindow; i++ {
+		apply(result(100))
+	}
+	if ring := d.windows[endpointKey(&checker.Result{Name: "api"})]; len(ring.samples) != anomalyWindow || ring.percentile(95) != 100*time.Millisecond {
+		t.Errorf("window = %d samples, p95 %v, want %d and 100ms", len(ring.samples), ring.percentile(95), anomalyWindow)
+	}
+	if w := apply(result(250)); len(w) != 1 {
+		t.Errorf("250ms warnings = %v, want anomaly against 100ms p95", w)
+	}
+
+	// An endpoint sharing the name but not the URL has its own window
+	other := result(2000)
+	other.URL = "https://other.example.com"
+	if w := apply(other); len(w) != 0 {
+		t.Errorf("same name, other URL warnings = %v, want none", w)
+	}
+}
+
+// TestEMATracker tests the moving average of each endpoint's latency across cycles
+func TestEMATracker(t *testing.T) {
+	status := 200
+	result := func(name string, ms int) checker.Result {
+		return checker.Result{Name: name, Healthy: true, StatusCode: &status, Latency: time.Duration(ms) * time.Millisecond}
+	}
+	tr := NewEMATracker(0.5)
+	apply := func(results ...checker.Result) []time.Duration {
+		batch := checker.BatchResult{Results: results}
+		tr.Apply(&batch)
+		emas := make([]time.Duration, len(batch.Results))
+		for i, r := range batch.Results {
+			emas[i] = r.LatencyEMA
+		}
+		return emas
+	}
+
+	tests := []struct {
+		name    string
+		results []checker.Result
+		want    []time.Duration
+	}{
+		{"first value starts each average", []checker.Result{result("api", 100), result("web", 40)}, []time.Duration{100 * time.Millisecond, 40 * time.Millisecond}},
+		{"spike is smoothed", []checker.Result{result("api", 300), result("web", 40)}, []time.Duration{200 * time.Millisecond, 40 * time.Millisecond}},
+		{"no response keeps the average", []checker.Result{{Name: "api", Latency: time.Minute}, result("web", 40)}, []time.Duration{200 * time.Millisecond, 40 * time.Millisecond}},
+		{"average follows recovery", []checker.Result{result("api", 100), result("new", 10)}, []time.Duration{150 * time.Millisecond, 10 * time.Millisecond}},
+		{"same name, other URL", []checker.Result{result("api", 100), {Name: "api", URL: "https://other.example.com", StatusCode: &status, Latency: 20 * time.Millisecond}},
+			[]time.Duration{125 * time.Millisecond, 20 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		if got := apply(tt.results...); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: LatencyEMA = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// An endpoint that never responded has no average
+	if got := apply(checker.Result{Name: "down"}); got[0] != 0 {
+		t.Errorf("never responded LatencyEMA = %v, want 0", got[0])
+	}
+}
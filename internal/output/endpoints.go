@@ -0,0 +1,198 @@
+// Resolved endpoint configuration
+// Describes the endpoints behind a batch in JSON output, see WithConfig
+package output
+
+import (
+	"maps"
+	"net/url"
+	"strconv"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// WithConfig embeds the resolved endpoints under a "config" key in batch output
+// Mask them first with MaskEndpoints unless secrets should be written as-is
+func WithConfig(endpoints []checker.Endpoint) JSONOption {
+	return func(f *JSONFormatter) {
+		f.config = endpoints
+	}
+}
+
+// endpointJSON is the JSON structure for a resolved endpoint, using config file key names
+type endpointJSON struct {
+	Name                  string            `json:"name"`
+	URL                   string            `json:"url"`
+	Method                string            `json:"method"`
+	Timeout               string            `json:"timeout"`
+	DegradedLatency       string            `json:"degraded_latency,omitempty"`
+	MaxLatency            string            `json:"max_latency,omitempty"`
+	Retries               int               `json:"retries"`
+	ExpectedStatus        []string          `json:"expected_status,omitempty"`
+	StatusByMethod        map[string]int    `json:"expected_status_by_method,omitempty"`
+	ForbiddenStatus       []int             `json:"forbidden_status,omitempty"`
+	ExpectedProto         string            `json:"expected_proto,omitempty"`
+	FollowRedirects       bool              `json:"follow_redirects"`
+	MaxRedirects          int               `json:"max_redirects,omitempty"`
+	Insecure              bool              `json:"insecure"`
+	ConnectOnly           bool              `json:"connect_only,omitempty"`
+	TLSServerName         string            `json:"tls_server_name,omitempty"`
+	TLSALPN               []string          `json:"tls_alpn,omitempty"`
+	CACert                string            `json:"ca_cert,omitempty"`
+	AddCACert             string            `json:"add_cacert,omitempty"`
+	CompleteChain         bool              `json:"require_complete_chain,omitempty"`
+	ClientCertP12         string            `json:"client_cert_p12,omitempty"`
+	ClientCertP12Password string            `json:"client_cert_p12_password,omitempty"`
+	ClientCert            string            `json:"client_cert,omitempty"`
+	ClientKey             string            `json:"client_key,omitempty"`
+	Proxy                 string            `json:"proxy,omitempty"`
+	NoProxy               bool              `json:"no_proxy,omitempty"`
+	Resolve               []string          `json:"resolve,omitempty"`
+	MaxHeaderBytes        int64             `json:"max_header_bytes,omitempty"`
+	Headers               map[string]string `json:"headers,omitempty"`
+	Body                  string            `json:"body,omitempty"`
+	AuthType              string            `json:"auth_type,omitempty"`
+	Username              string            `json:"username,omitempty"`
+	Password              string            `json:"password,omitempty"`
+	AWSRegion             string            `json:"aws_region,omitempty"`
+	AWSService            string            `json:"aws_service,omitempty"`
+	Tags                  []string          `json:"tags,omitempty"`
+	Accept                string            `json:"accept,omitempty"`
+	RequireContentType    string            `json:"require_content_type,omitempty"`
+	HeaderAssert          map[string]string `json:"header_assert,omitempty"`
+	ExpectedBody          *string           `json:"expected_body,omitempty"`
+	NormalizeWhitespace   bool              `json:"normalize_whitespace,omitempty"`
+	ExpectedBodySHA256    string            `json:"expected_body_sha256,omitempty"`
+	BodyContains          string            `json:"body_contains,omitempty"`
+	BodyRegex             string            `json:"body_regex,omitempty"`
+	JSONAssert            *jsonAssertJSON   `json:"json_assert,omitempty"`
+	Capture               map[string]string `json:"capture,omitempty"`
+	MinBodySize           int               `json:"min_body_size,omitempty"`
+	MaxBodySize           int               `json:"max_body_size,omitempty"`
+	ExpectedLocation      string            `json:"expected_location,omitempty"`
+	ExpectedLocationRegex string            `json:"expected_location_regex,omitempty"`
+	Schedule              string            `json:"schedule,omitempty"`
+}
+
+// jsonAssertJSON is the JSON structure for a JSON body assertion
+type jsonAssertJSON struct {
+	Path   string `json:"path"`
+	Equals string `json:"equals"`
+}
+
+// MaskEndpoints returns copies of endpoints with secrets masked: secret query parameter
+// values in URLs, secret header values, passwords and request bodies
+func MaskEndpoints(endpoints []checker.Endpoint) []checker.Endpoint {
+	masked := make([]checker.Endpoint, len(endpoints))
+	for i, ep := range endpoints {
+		ep.Name = sanitizeURL(ep.Name)
+		ep.URL = sanitizeURL(ep.URL)
+		if len(ep.Headers) > 0 {
+			ep.Headers = maps.Clone(ep.Headers)
+			for k, v := range ep.Headers {
+				ep.Headers[k] = checker.MaskHeader(k, v)
+			}
+		}
+		if ep.Password != "" {
+			ep.Password = checker.MaskedValue
+		}
+		if ep.ClientCertP12Pass != "" {
+			ep.ClientCertP12Pass = checker.MaskedValue
+		}
+		if proxy, err := url.Parse(ep.Proxy); err == nil {
+			ep.Proxy = proxy.Redacted()
+		}
+		// Bodies often carry credentials, e.g. login requests
+		if ep.Body != "" {
+			ep.Body = checker.MaskedValue
+		}
+		masked[i] = ep
+	}
+	return masked
+}
+
+// convertEndpoints converts resolved endpoints to their JSON structure
+func convertEndpoints(endpoints []checker.Endpoint) []endpointJSON {
+	if endpoints == nil {
+		return nil
+	}
+	out := make([]endpointJSON, len(endpoints))
+	for i, ep := range endpoints {
+		method := ep.Method
+		if method == "" {
+			method = "GET"
+		}
+		item := endpointJSON{
+			Name:                  ep.Name,
+			URL:                   ep.URL,
+			Method:                method,
+			Timeout:               ep.Timeout.String(),
+			Retries:               ep.Retries,
+			StatusByMethod:        ep.StatusByMethod,
+			ForbiddenStatus:       ep.ForbiddenStatus,
+			ExpectedProto:         ep.ExpectedProto,
+			FollowRedirects:       ep.FollowRedirects,
+			MaxRedirects:          ep.MaxRedirects,
+			Insecure:              ep.Insecure,
+			ConnectOnly:           ep.ConnectOnly,
+			TLSServerName:         ep.TLSServerName,
+			TLSALPN:               ep.TLSALPN,
+			CACert:                ep.CACert,
+			AddCACert:             ep.AddCACert,
+			CompleteChain:         ep.CompleteChain,
+			ClientCertP12:         ep.ClientCertP12,
+			ClientCertP12Password: ep.ClientCertP12Pass,
+			ClientCert:            ep.ClientCert,
+			ClientKey:             ep.ClientKey,
+			Proxy:                 ep.Proxy,
+			NoProxy:               ep.NoProxy,
+			Resolve:               ep.Resolve,
+			MaxHeaderBytes:        ep.MaxHeaderBytes,
+			Headers:               ep.Headers,
+			Body:                  ep.Body,
+			AuthType:              ep.AuthType,
+			Username:              ep.Username,
+			Password:              ep.Password,
+			AWSRegion:             ep.AWSRegion,
+			AWSService:            ep.AWSService,
+			Tags:                  ep.Tags,
+			Accept:                ep.Accept,
+			RequireContentType:    ep.RequireContentType,
+			HeaderAssert:          ep.HeaderAssert,
+			ExpectedBody:          ep.ExpectedBody,
+			NormalizeWhitespace:   ep.NormalizeWhitespace,
+			ExpectedBodySHA256:    ep.ExpectedBodySHA256,
+			BodyContains:          ep.BodyContains,
+			Capture:               ep.Capture,
+			MinBodySize:           ep.MinBodyBytes,
+			MaxBodySize:           ep.MaxBodyBytes,
+			ExpectedLocation:      ep.ExpectedLocation,
+			Schedule:              ep.Schedule,
+		}
+		if ep.DegradedLatency > 0 {
+			item.DegradedLatency = ep.DegradedLatency.String()
+		}
+		if ep.MaxLatency > 0 {
+			item.MaxLatency = ep.MaxLatency.String()
+		}
+		for _, code := range ep.ExpectedStatuses {
+			item.ExpectedStatus = append(item.ExpectedStatus, strconv.Itoa(code))
+		}
+		for _, r := range ep.ExpectedRanges {
+			item.ExpectedStatus = append(item.ExpectedStatus, r.String())
+		}
+		if item.ExpectedStatus == nil && ep.ExpectedStatus != 0 {
+			item.ExpectedStatus = []string{strconv.Itoa(ep.ExpectedStatus)}
+		}
+		if ep.BodyRegex != nil {
+			item.BodyRegex = ep.BodyRegex.String()
+		}
+		if ep.JSONAssert != nil {
+			item.JSONAssert = &jsonAssertJSON{Path: ep.JSONAssert.Path, Equals: ep.JSONAssert.Equals}
+		}
+		if ep.LocationPattern != nil {
+			item.ExpectedLocationRegex = ep.LocationPattern.String()
+		}
+		out[i] = item
+	}
+	return out
+}
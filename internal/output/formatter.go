@@ -3,7 +3,9 @@
 package output
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
 )
@@ -21,18 +23,47 @@ type Formatter interface {
 type OutputFormat string
 
 const (
-	FormatTable OutputFormat = "table"
-	FormatJSON  OutputFormat = "json"
+	FormatTable  OutputFormat = "table"
+	FormatJSON   OutputFormat = "json"
+	FormatInflux OutputFormat = "influx"
 )
 
 // NewFormatter creates a formatter based on format type
-func NewFormatter(format OutputFormat, w io.Writer, noColor bool) Formatter {
+// Table options are ignored by other formats
+func NewFormatter(format OutputFormat, w io.Writer, noColor bool, tableOpts ...TableOption) Formatter {
 	switch format {
 	case FormatJSON:
 		return NewJSONFormatter(w)
+	case FormatInflux:
+		return NewInfluxFormatter(w)
 	case FormatTable:
 		fallthrough
 	default:
-		return NewTableFormatter(w, noColor)
+		return NewTableFormatter(w, noColor, tableOpts...)
 	}
 }
+
+// StdoutPath is the OutputSpec path meaning standard output
+const StdoutPath = "-"
+
+// OutputSpec is a parsed output destination in "format[:path]" form
+type OutputSpec struct {
+	Format OutputFormat
+	Path   string // File path, or StdoutPath for standard output
+}
+
+// ParseOutputSpec parses an output spec such as "table", "table:-" or "json:results.json"
+func ParseOutputSpec(spec string) (OutputSpec, error) {
+	format, path, _ := strings.Cut(spec, ":")
+	if path == "" {
+		path = StdoutPath
+	}
+
+	switch OutputFormat(format) {
+	case FormatTable, FormatJSON, FormatInflux:
+	default:
+		return OutputSpec{}, fmt.Errorf("invalid output '%s': unknown format '%s' (expected table, json or influx)", spec, format)
+	}
+
+	return OutputSpec{Format: OutputFormat(format), Path: path}, nil
+}
@@ -0,0 +1,104 @@
+// InfluxDB line protocol output
+// Writes one point per result for InfluxDB, e.g. through Telegraf's exec input
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// Measurement names written by InfluxFormatter
+const (
+	influxMeasurement        = "healthcheck"
+	influxSummaryMeasurement = "healthcheck_summary"
+)
+
+// InfluxFormatter implements InfluxDB line protocol output
+type InfluxFormatter struct {
+	writer io.Writer
+}
+
+// NewInfluxFormatter creates an InfluxDB line protocol formatter
+func NewInfluxFormatter(w io.Writer) *InfluxFormatter {
+	return &InfluxFormatter{writer: w}
+}
+
+// Line protocol escaping: tag values escape commas, equals signs and spaces,
+// string field values escape quotes and backslashes
+// Newlines end a line and can't be escaped anywhere, so they become spaces
+var (
+	influxTagEscaper    = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", " ", "\r", " ")
+	influxStringEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, "\n", " ", "\r", " ")
+)
+
+// FormatSingle writes a single result as one point, timestamped when the check started
+func (f *InfluxFormatter) FormatSingle(result checker.Result) error {
+	ts := result.CheckedAt
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	_, err := io.WriteString(f.writer, influxLine(result, ts))
+	return err
+}
+
+// FormatBatch writes a point per result followed by a healthcheck_summary point
+// Results share the batch timestamp unless per-check timestamps were recorded
+func (f *InfluxFormatter) FormatBatch(result checker.BatchResult) error {
+	ts := result.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	var b strings.Builder
+	for _, r := range result.Results {
+		rts := ts
+		if !r.CheckedAt.IsZero() {
+			rts = r.CheckedAt
+		}
+		b.WriteString(influxLine(r, rts))
+	}
+
+	s := result.Summary
+	fmt.Fprintf(&b, "%s total=%di,healthy=%di,degraded=%di,unhealthy=%di,duration_ms=%di %d\n",
+		influxSummaryMeasurement, s.Total, s.Healthy, s.Degraded, s.Unhealthy,
+		s.Duration.Milliseconds(), ts.UnixNano())
+
+	_, err := io.WriteString(f.writer, b.String())
+	return err
+}
+
+// influxLine formats a result as a healthcheck point tagged with its name, URL and state
+// Latency and status code are only written when the endpoint responded
+func influxLine(r checker.Result, ts time.Time) string {
+	var b strings.Builder
+	b.WriteString(influxMeasurement)
+	// Empty tag values aren't allowed, so those tags are left out
+	for _, tag := range [][2]string{{"name", r.Name}, {"url", r.URL}, {"state", string(r.State())}} {
+		if tag[1] != "" {
+			fmt.Fprintf(&b, ",%s=%s", tag[0], influxTagEscaper.Replace(tag[1]))
+		}
+	}
+
+	up := 0
+	if r.Healthy {
+		up = 1
+	}
+	fmt.Fprintf(&b, " up=%di", up)
+	if r.Healthy || r.StatusCode != nil {
+		fmt.Fprintf(&b, ",latency_ms=%di", r.Latency.Milliseconds())
+	}
+	if r.StatusCode != nil {
+		fmt.Fprintf(&b, ",status_code=%di", *r.StatusCode)
+	}
+	if r.Error != nil {
+		fmt.Fprintf(&b, `,error="%s",error_kind="%s"`,
+			influxStringEscaper.Replace(r.Error.Error()), influxStringEscaper.Replace(string(r.ErrorKind)))
+	}
+
+	fmt.Fprintf(&b, " %d\n", ts.UnixNano())
+	return b.String()
+}
@@ -5,6 +5,7 @@ package output
 import (
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
 )
@@ -12,22 +13,82 @@ import (
 // JSONFormatter implements JSON format output
 type JSONFormatter struct {
 	writer io.Writer
+	config []checker.Endpoint // Resolved endpoints embedded in batch output, see WithConfig
+	cert   bool               // Always include the tls key, see WithCert
 }
 
+// JSONOption is JSON formatter configuration option
+type JSONOption func(*JSONFormatter)
+
 // NewJSONFormatter creates a JSON formatter
-func NewJSONFormatter(w io.Writer) *JSONFormatter {
-	return &JSONFormatter{
+func NewJSONFormatter(w io.Writer, opts ...JSONOption) *JSONFormatter {
+	f := &JSONFormatter{
 		writer: w,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// WithCert adds a "tls" key with each result's certificate, null for endpoints without TLS
+func WithCert(enabled bool) JSONOption {
+	return func(f *JSONFormatter) {
+		f.cert = enabled
+	}
 }
 
 // singleResultJSON is the JSON structure for single result
 type singleResultJSON struct {
-	URL        string  `json:"url"`
-	Healthy    bool    `json:"healthy"`
-	StatusCode *int    `json:"status_code"`
-	LatencyMs  *int64  `json:"latency_ms"`
-	Error      *string `json:"error"`
+	URL          string             `json:"url"`
+	Healthy      bool               `json:"healthy"`
+	State        checker.State      `json:"state"`
+	StatusCode   *int               `json:"status_code"`
+	LatencyMs    *int64             `json:"latency_ms"`
+	Error        *string            `json:"error"`
+	ErrorKind    checker.ErrorKind  `json:"error_kind,omitempty"`
+	FinalURL     string             `json:"final_url,omitempty"`
+	Redirects    []string           `json:"redirects,omitempty"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	Assertions   []assertionJSON    `json:"assertions,omitempty"`
+	DNSResolved  []string           `json:"dns_resolved,omitempty"`
+	DNSLatencyMs *float64           `json:"dns_latency_ms,omitempty"`
+	Timings      *timingsJSON       `json:"timings,omitempty"`
+	ServerTiming []serverTimingJSON `json:"server_timing,omitempty"`
+	TLS          json.RawMessage    `json:"tls,omitempty"`
+	OCSP         checker.OCSPStatus `json:"ocsp,omitempty"`
+}
+
+// certJSON is the JSON structure for a served certificate
+type certJSON struct {
+	Subject   string   `json:"subject"`
+	Issuer    string   `json:"issuer"`
+	SANs      []string `json:"sans"`
+	NotBefore string   `json:"not_before"`
+	NotAfter  string   `json:"not_after"`
+}
+
+// assertionJSON is the JSON structure for an assertion outcome
+type assertionJSON struct {
+	Type   checker.AssertionType `json:"type"`
+	Passed bool                  `json:"passed"`
+	Detail string                `json:"detail"`
+}
+
+// timingsJSON is the JSON structure for connection phase timings
+type timingsJSON struct {
+	DNSMs      float64 `json:"dns_ms"`
+	ConnectMs  float64 `json:"connect_ms"`
+	TLSMs      float64 `json:"tls_ms"`
+	TTFBMs     float64 `json:"ttfb_ms"`
+	ConnReused bool    `json:"conn_reused"`
+}
+
+// serverTimingJSON is the JSON structure for a Server-Timing metric
+type serverTimingJSON struct {
+	Name        string  `json:"name"`
+	DurationMs  float64 `json:"duration_ms"`
+	Description string  `json:"description,omitempty"`
 }
 
 // batchResultJSON is the JSON structure for batch results
@@ -36,23 +97,58 @@ type batchResultJSON struct {
 	DurationMs int64             `json:"duration_ms"`
 	Summary    summaryJSON       `json:"summary"`
 	Results    []resultItemJSON  `json:"results"`
+	Config     []endpointJSON    `json:"config,omitempty"`
 }
 
 // summaryJSON is the JSON structure for summary information
 type summaryJSON struct {
-	Total     int `json:"total"`
-	Healthy   int `json:"healthy"`
-	Unhealthy int `json:"unhealthy"`
+	Total          int                       `json:"total"`
+	Healthy        int                       `json:"healthy"`
+	Degraded       int                       `json:"degraded,omitempty"`
+	Unhealthy      int                       `json:"unhealthy"`
+	FailuresByKind map[checker.ErrorKind]int `json:"failures_by_kind,omitempty"`
+	Concurrency    int                       `json:"concurrency,omitempty"`
+	Slowest        *slowestJSON              `json:"slowest,omitempty"`
+}
+
+// slowestJSON is the JSON structure for the slowest endpoint of a batch
+type slowestJSON struct {
+	Name      string `json:"name"`
+	LatencyMs int64  `json:"latency_ms"`
 }
 
 // resultItemJSON is the JSON structure for result item
 type resultItemJSON struct {
-	Name       string  `json:"name"`
-	URL        string  `json:"url"`
-	Healthy    bool    `json:"healthy"`
-	StatusCode *int    `json:"status_code"`
-	LatencyMs  *int64  `json:"latency_ms"`
-	Error      *string `json:"error"`
+	Name         string             `json:"name"`
+	URL          string             `json:"url"`
+	Healthy      bool               `json:"healthy"`
+	State        checker.State      `json:"state"`
+	StatusCode   *int               `json:"status_code"`
+	LatencyMs    *int64             `json:"latency_ms"`
+	CheckedAt    string             `json:"checked_at,omitempty"`
+	CompletedAt  string             `json:"completed_at,omitempty"`
+	Error        *string            `json:"error"`
+	ErrorKind    checker.ErrorKind  `json:"error_kind,omitempty"`
+	FinalURL     string             `json:"final_url,omitempty"`
+	Redirects    []string           `json:"redirects,omitempty"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	Assertions   []assertionJSON    `json:"assertions,omitempty"`
+	ALPN         string             `json:"alpn,omitempty"`
+	TLS          json.RawMessage    `json:"tls,omitempty"`
+	OCSP         checker.OCSPStatus `json:"ocsp,omitempty"`
+	DNSResolved  []string           `json:"dns_resolved,omitempty"`
+	DNSLatencyMs *float64           `json:"dns_latency_ms,omitempty"`
+	Timings      *timingsJSON       `json:"timings,omitempty"`
+	ServerTiming []serverTimingJSON `json:"server_timing,omitempty"`
+	Cache        *cacheJSON         `json:"cache,omitempty"`
+}
+
+// cacheJSON is the JSON structure for a cache check
+type cacheJSON struct {
+	ColdLatencyMs int64  `json:"cold_latency_ms"`
+	WarmLatencyMs int64  `json:"warm_latency_ms"`
+	Header        string `json:"header"`
+	Hit           bool   `json:"hit"`
 }
 
 // FormatSingle formats a single check result
@@ -60,6 +156,7 @@ func (f *JSONFormatter) FormatSingle(result checker.Result) error {
 	output := singleResultJSON{
 		URL:        result.URL,
 		Healthy:    result.Healthy,
+		State:      result.State(),
 		StatusCode: result.StatusCode,
 	}
 
@@ -73,8 +170,20 @@ func (f *JSONFormatter) FormatSingle(result checker.Result) error {
 	if result.Error != nil {
 		errStr := result.Error.Error()
 		output.Error = &errStr
+		output.ErrorKind = result.ErrorKind
 	}
 
+	output.FinalURL = result.FinalURL
+	output.Redirects = result.Redirects
+	output.Warnings = result.Warnings
+	output.Assertions = convertAssertions(result.Assertions)
+	output.DNSResolved = result.DNSResolved
+	output.DNSLatencyMs = dnsLatencyMs(result)
+	output.Timings = convertTimings(result.Timings)
+	output.ServerTiming = convertServerTiming(result.ServerTiming)
+	output.TLS = f.convertCert(result.Cert)
+	output.OCSP = result.OCSP
+
 	encoder := json.NewEncoder(f.writer)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
@@ -86,11 +195,16 @@ func (f *JSONFormatter) FormatBatch(batch checker.BatchResult) error {
 		Timestamp:  batch.Timestamp.Format("2006-01-02T15:04:05Z"),
 		DurationMs: batch.Summary.Duration.Milliseconds(),
 		Summary: summaryJSON{
-			Total:     batch.Summary.Total,
-			Healthy:   batch.Summary.Healthy,
-			Unhealthy: batch.Summary.Unhealthy,
+			Total:          batch.Summary.Total,
+			Healthy:        batch.Summary.Healthy,
+			Degraded:       batch.Summary.Degraded,
+			Unhealthy:      batch.Summary.Unhealthy,
+			FailuresByKind: batch.Summary.FailuresByKind,
+			Concurrency:    batch.Summary.Concurrency,
+			Slowest:        convertSlowest(batch.Summary.Slowest),
 		},
 		Results: make([]resultItemJSON, len(batch.Results)),
+		Config:  convertEndpoints(f.config),
 	}
 
 	// Convert each result
@@ -99,6 +213,7 @@ func (f *JSONFormatter) FormatBatch(batch checker.BatchResult) error {
 			Name:       result.Name,
 			URL:        result.URL,
 			Healthy:    result.Healthy,
+			State:      result.State(),
 			StatusCode: result.StatusCode,
 		}
 
@@ -112,6 +227,33 @@ func (f *JSONFormatter) FormatBatch(batch checker.BatchResult) error {
 		if result.Error != nil {
 			errStr := result.Error.Error()
 			item.Error = &errStr
+			item.ErrorKind = result.ErrorKind
+		}
+
+		// Per-result timestamps, only present when recorded
+		item.CheckedAt = formatTimestamp(result.CheckedAt)
+		item.CompletedAt = formatTimestamp(result.CompletedAt)
+
+		item.FinalURL = result.FinalURL
+		item.Redirects = result.Redirects
+		item.Warnings = result.Warnings
+		item.Assertions = convertAssertions(result.Assertions)
+		item.ALPN = result.ALPN
+		item.TLS = f.convertCert(result.Cert)
+		item.OCSP = result.OCSP
+		item.DNSResolved = result.DNSResolved
+		item.DNSLatencyMs = dnsLatencyMs(result)
+		item.Timings = convertTimings(result.Timings)
+		item.ServerTiming = convertServerTiming(result.ServerTiming)
+
+		// Cache check details, only present once both requests ran
+		if c := result.Cache; c != nil && c.ColdLatency > 0 {
+			item.Cache = &cacheJSON{
+				ColdLatencyMs: c.ColdLatency.Milliseconds(),
+				WarmLatencyMs: c.WarmLatency.Milliseconds(),
+				Header:        c.Header,
+				Hit:           c.Hit,
+			}
 		}
 
 		output.Results[i] = item
@@ -121,3 +263,102 @@ func (f *JSONFormatter) FormatBatch(batch checker.BatchResult) error {
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
+
+// convertCert encodes a certificate for the "tls" key: omitted unless WithCert is set, null without TLS
+func (f *JSONFormatter) convertCert(c *checker.CertInfo) json.RawMessage {
+	if !f.cert {
+		return nil
+	}
+	if c == nil {
+		return json.RawMessage("null")
+	}
+	sans := c.DNSNames
+	if sans == nil {
+		sans = []string{}
+	}
+	data, err := json.Marshal(certJSON{
+		Subject:   c.Subject,
+		Issuer:    c.Issuer,
+		SANs:      sans,
+		NotBefore: c.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:  c.NotAfter.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// convertSlowest converts the slowest endpoint, keeping nil for empty batches
+func convertSlowest(s *checker.Slowest) *slowestJSON {
+	if s == nil {
+		return nil
+	}
+	return &slowestJSON{Name: s.Name, LatencyMs: s.Latency.Milliseconds()}
+}
+
+// convertTimings converts connection phase timings to milliseconds
+func convertTimings(t *checker.Timings) *timingsJSON {
+	if t == nil {
+		return nil
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	return &timingsJSON{
+		DNSMs:      ms(t.DNS),
+		ConnectMs:  ms(t.Connect),
+		TLSMs:      ms(t.TLS),
+		TTFBMs:     ms(t.TTFB),
+		ConnReused: t.ConnReused,
+	}
+}
+
+// convertServerTiming converts Server-Timing metrics to JSON structures
+func convertServerTiming(metrics []checker.ServerTimingMetric) []serverTimingJSON {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	items := make([]serverTimingJSON, len(metrics))
+	for i, m := range metrics {
+		items[i] = serverTimingJSON{
+			Name:        m.Name,
+			DurationMs:  float64(m.Duration) / float64(time.Millisecond),
+			Description: m.Description,
+		}
+	}
+	return items
+}
+
+// convertAssertions converts assertion outcomes to JSON structures
+func convertAssertions(assertions []checker.AssertionResult) []assertionJSON {
+	if len(assertions) == 0 {
+		return nil
+	}
+
+	items := make([]assertionJSON, len(assertions))
+	for i, a := range assertions {
+		items[i] = assertionJSON{
+			Type:   a.Type,
+			Passed: a.Passed,
+			Detail: a.Detail,
+		}
+	}
+	return items
+}
+
+// dnsLatencyMs returns the DNS lookup time in milliseconds, or nil when DNS was not probed
+func dnsLatencyMs(result checker.Result) *float64 {
+	if result.DNSResolved == nil && result.DNSLatency == 0 {
+		return nil
+	}
+	ms := float64(result.DNSLatency) / float64(time.Millisecond)
+	return &ms
+}
+
+// formatTimestamp formats a time as RFC 3339 UTC with milliseconds, or "" for the zero time
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
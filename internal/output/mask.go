@@ -0,0 +1,62 @@
+// URL masking
+// Hides secret query parameter values before results are printed or saved
+package output
+
+import (
+	"strings"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// MaskBatch returns a copy of batch with secret query parameter values masked in every result
+// and in the slowest endpoint's name
+func MaskBatch(batch checker.BatchResult) checker.BatchResult {
+	results := make([]checker.Result, len(batch.Results))
+	for i, r := range batch.Results {
+		results[i] = MaskResult(r)
+	}
+	batch.Results = results
+	if s := batch.Summary.Slowest; s != nil {
+		batch.Summary.Slowest = &checker.Slowest{Name: sanitizeURL(s.Name), Latency: s.Latency}
+	}
+	return batch
+}
+
+// MaskResult returns a copy of r with secret query parameter values masked
+// in its URL, its name (which defaults to the URL), its redirects and its error message
+func MaskResult(r checker.Result) checker.Result {
+	r.FinalURL = sanitizeURL(r.FinalURL)
+	if len(r.Redirects) > 0 {
+		redirects := make([]string, len(r.Redirects))
+		for i, u := range r.Redirects {
+			redirects[i] = sanitizeURL(u)
+		}
+		r.Redirects = redirects
+	}
+
+	masked := sanitizeURL(r.URL)
+	if masked == r.URL {
+		return r
+	}
+
+	if r.Error != nil && strings.Contains(r.Error.Error(), r.URL) {
+		r.Error = &maskedError{msg: strings.ReplaceAll(r.Error.Error(), r.URL, masked), err: r.Error}
+	}
+	r.Name = sanitizeURL(r.Name)
+	r.URL = masked
+	return r
+}
+
+// maskedError replaces an error message while keeping the original error for errors.Is
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string { return e.msg }
+func (e *maskedError) Unwrap() error { return e.err }
+
+// sanitizeURL masks values of query parameters whose names look secret, e.g. ?api_key=***
+func sanitizeURL(raw string) string {
+	return checker.MaskURL(raw)
+}
@@ -0,0 +1,128 @@
+// Nagios plugin output
+// Prints a single status line with performance data for Nagios and Icinga
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// NagiosState is a Nagios plugin state; its value is the plugin exit code
+type NagiosState int
+
+// Nagios plugin states
+const (
+	NagiosOK       NagiosState = 0
+	NagiosWarning  NagiosState = 1
+	NagiosCritical NagiosState = 2
+	NagiosUnknown  NagiosState = 3
+)
+
+// String returns the state name used at the start of the status line
+func (s NagiosState) String() string {
+	switch s {
+	case NagiosOK:
+		return "OK"
+	case NagiosWarning:
+		return "WARNING"
+	case NagiosCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// NagiosThresholds are the unhealthy endpoint counts at which a batch turns WARNING or CRITICAL
+// MaxLatency, if set, is the latency above which a responding endpoint makes the batch WARNING
+type NagiosThresholds struct {
+	Warning    int
+	Critical   int
+	MaxLatency time.Duration
+}
+
+// NagiosStatus returns the state of a batch: CRITICAL or WARNING once the unhealthy count
+// reaches the threshold, WARNING also when any endpoint is degraded or slow, OK otherwise
+func NagiosStatus(batch checker.BatchResult, t NagiosThresholds) NagiosState {
+	switch {
+	case batch.Summary.Unhealthy >= t.Critical:
+		return NagiosCritical
+	case batch.Summary.Unhealthy >= t.Warning, batch.Summary.Degraded > 0, len(nagiosSlow(batch.Results, t)) > 0:
+		return NagiosWarning
+	default:
+		return NagiosOK
+	}
+}
+
+// nagiosSlow lists endpoints that are up but slower than the MaxLatency threshold
+func nagiosSlow(results []checker.Result, t NagiosThresholds) []string {
+	if t.MaxLatency <= 0 {
+		return nil
+	}
+	var slow []string
+	for _, r := range results {
+		if r.State() != checker.StateDown && r.Latency > t.MaxLatency {
+			slow = append(slow, r.Name)
+		}
+	}
+	return slow
+}
+
+// WriteNagios prints the batch as a Nagios status line, e.g.
+// "CRITICAL - 48/50 healthy, down: API, DB | healthy=48;;;0;50 ..." and returns its state
+// Performance data holds the health counts and each responding endpoint's latency
+func WriteNagios(w io.Writer, batch checker.BatchResult, t NagiosThresholds) (NagiosState, error) {
+	state := NagiosStatus(batch, t)
+	s := batch.Summary
+
+	var down, degraded []string
+	for _, r := range batch.Results {
+		switch r.State() {
+		case checker.StateDown:
+			down = append(down, r.Name)
+		case checker.StateDegraded:
+			degraded = append(degraded, r.Name)
+		}
+	}
+
+	line := fmt.Sprintf("%s - %d/%d healthy", state, s.Healthy, s.Total)
+	if len(down) > 0 {
+		line += ", down: " + strings.Join(down, ", ")
+	}
+	if len(degraded) > 0 {
+		line += ", degraded: " + strings.Join(degraded, ", ")
+	}
+	if slow := nagiosSlow(batch.Results, t); len(slow) > 0 {
+		line += ", slow: " + strings.Join(slow, ", ")
+	}
+
+	perf := []string{
+		fmt.Sprintf("healthy=%d;;;0;%d", s.Healthy, s.Total),
+		fmt.Sprintf("unhealthy=%d;%d;%d;0;%d", s.Unhealthy, t.Warning, t.Critical, s.Total),
+		fmt.Sprintf("degraded=%d;;;0;%d", s.Degraded, s.Total),
+	}
+	// The latency warning threshold goes in the warn field, when set
+	latencyWarn := ""
+	if t.MaxLatency > 0 {
+		latencyWarn = fmt.Sprint(t.MaxLatency.Milliseconds())
+	}
+	for _, r := range batch.Results {
+		if r.StatusCode != nil {
+			perf = append(perf, fmt.Sprintf("%s=%dms;%s;;0", nagiosLabel(r.Name), r.Latency.Milliseconds(), latencyWarn))
+		}
+	}
+
+	// A pipe in an endpoint name would start the performance data early
+	line = strings.ReplaceAll(line, "|", "/")
+	_, err := fmt.Fprintf(w, "%s | %s\n", line, strings.Join(perf, " "))
+	return state, err
+}
+
+// nagiosLabel quotes a performance data label, doubling single quotes and dropping '=' and '|'
+func nagiosLabel(name string) string {
+	name = strings.NewReplacer("'", "''", "=", "_", "|", "_").Replace(name)
+	return "'" + name + "'"
+}
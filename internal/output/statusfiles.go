@@ -0,0 +1,52 @@
+// Status files
+// Writes one exit-status file per endpoint for CI matrix jobs
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// unsafeFileChars matches characters replaced in status file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// StatusFileName returns the status file name for an endpoint
+// Characters other than letters, digits, '.', '_' and '-' become '_'
+func StatusFileName(name string) string {
+	return unsafeFileChars.ReplaceAllString(name, "_") + ".status"
+}
+
+// WriteStatusFiles writes "<name>.status" containing 0 (healthy) or 1 (unhealthy) for each result
+// The directory is created if needed
+func WriteStatusFiles(dir string, results []checker.Result) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create status file directory: %w", err)
+	}
+
+	// Reject names that collide after sanitizing rather than overwrite silently
+	seen := make(map[string]string, len(results))
+	for _, r := range results {
+		file := StatusFileName(r.Name)
+		if other, ok := seen[file]; ok {
+			return fmt.Errorf("endpoints '%s' and '%s' map to the same status file %s", other, r.Name, file)
+		}
+		seen[file] = r.Name
+	}
+
+	for _, r := range results {
+		status := "1"
+		if r.Healthy {
+			status = "0"
+		}
+		path := filepath.Join(dir, StatusFileName(r.Name))
+		if err := os.WriteFile(path, []byte(status+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write status file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,8 @@ package output
 import (
 	"fmt"
 	"io"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,24 +21,107 @@ const (
 	colorYellow = "\033[33m"
 )
 
-// Table column width limits
+// Default table column width limits
 const (
-	maxNameWidth = 30
-	maxURLWidth  = 50
+	defaultMaxNameWidth = 30
+	defaultMaxURLWidth  = 50
 )
 
+// Terminal fitting limits
+const (
+	fixedColumnsWidth = 24 // Separators, STATUS and LATENCY columns
+	minNameWidth      = 10
+	minURLWidth       = 15
+)
+
+// latencyWidth pads the LATENCY column when the EMA column follows it
+const latencyWidth = 8
+
 // TableFormatter implements table format output
 type TableFormatter struct {
-	writer  io.Writer
-	noColor bool
+	writer       io.Writer
+	noColor      bool
+	maxNameWidth int
+	maxURLWidth  int
+	sections     bool
+	verbose      bool
+	ema          bool
+
+	// Host tree view, see WithTree and WithCollapseHealthy
+	tree            bool
+	collapseHealthy bool
+
+	// Column widths fixed by BeginStream
+	streamNameWidth int
+	streamURLWidth  int
+}
+
+// TableOption is TableFormatter configuration option
+type TableOption func(*TableFormatter)
+
+// WithColumnWidths sets maximum NAME and URL column widths
+// Non-positive values keep the current width
+func WithColumnWidths(nameWidth, urlWidth int) TableOption {
+	return func(f *TableFormatter) {
+		if nameWidth > 0 {
+			f.maxNameWidth = nameWidth
+		}
+		if urlWidth > 0 {
+			f.maxURLWidth = urlWidth
+		}
+	}
+}
+
+// WithTerminalWidth fits NAME and URL columns to a terminal of the given width
+// URL gets two thirds of the available space; non-positive widths are ignored
+func WithTerminalWidth(cols int) TableOption {
+	return func(f *TableFormatter) {
+		if cols <= 0 {
+			return
+		}
+		available := cols - fixedColumnsWidth
+		nameWidth := max(available/3, minNameWidth)
+		urlWidth := max(available-nameWidth, minURLWidth)
+		f.maxNameWidth = nameWidth
+		f.maxURLWidth = urlWidth
+	}
+}
+
+// WithSections groups batch rows into FAILURES and OK sections, failures first
+func WithSections(enabled bool) TableOption {
+	return func(f *TableFormatter) {
+		f.sections = enabled
+	}
+}
+
+// WithVerbose lists failed assertions below each row
+func WithVerbose(enabled bool) TableOption {
+	return func(f *TableFormatter) {
+		f.verbose = enabled
+	}
+}
+
+// WithEMA adds an EMA column with each result's moving average latency
+func WithEMA(enabled bool) TableOption {
+	return func(f *TableFormatter) {
+		f.ema = enabled
+	}
 }
 
 // NewTableFormatter creates a table formatter
-func NewTableFormatter(w io.Writer, noColor bool) *TableFormatter {
-	return &TableFormatter{
-		writer:  w,
-		noColor: noColor,
+func NewTableFormatter(w io.Writer, noColor bool, opts ...TableOption) *TableFormatter {
+	f := &TableFormatter{
+		writer:       w,
+		noColor:      noColor,
+		maxNameWidth: defaultMaxNameWidth,
+		maxURLWidth:  defaultMaxURLWidth,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // FormatSingle formats a single check result
@@ -45,7 +130,7 @@ func (f *TableFormatter) FormatSingle(result checker.Result) error {
 	var latency string
 
 	if result.Healthy {
-		status = f.colorize("✓", colorGreen)
+		status = f.healthyMark(result)
 		if result.StatusCode != nil {
 			status += fmt.Sprintf(" %d", *result.StatusCode)
 		}
@@ -55,7 +140,7 @@ func (f *TableFormatter) FormatSingle(result checker.Result) error {
 			status += fmt.Sprintf(" %d", *result.StatusCode)
 		} else if result.Error != nil {
 			// Extract short error message
-			status += " " + f.getShortError(result.Error)
+			status += " " + f.getShortError(result)
 		}
 	}
 
@@ -65,17 +150,70 @@ func (f *TableFormatter) FormatSingle(result checker.Result) error {
 		latency = "--"
 	}
 
-	_, err := fmt.Fprintf(f.writer, "%s %s    %s\n", status, result.URL, latency)
-	return err
+	if _, err := fmt.Fprintf(f.writer, "%s %s    %s\n", status, result.URL, latency); err != nil {
+		return err
+	}
+	if err := f.formatAssertions(result.Assertions); err != nil {
+		return err
+	}
+	if err := f.formatRedirects(result.Redirects); err != nil {
+		return err
+	}
+	if err := f.formatDebug(result.Debug); err != nil {
+		return err
+	}
+	return f.formatWarnings(result.Warnings)
 }
 
 // FormatBatch formats batch check results
 func (f *TableFormatter) FormatBatch(batch checker.BatchResult) error {
-	// Calculate column widths
+	nameWidth, urlWidth := f.columnWidths(batch.Results)
+
+	// Print rows, optionally grouped by host or health
+	var err error
+	switch {
+	case f.tree:
+		err = f.formatTree(batch.Results)
+	case f.sections:
+		err = f.formatSections(batch.Results, nameWidth, urlWidth)
+	default:
+		err = f.formatRows(batch.Results, nameWidth, urlWidth)
+	}
+	if err != nil {
+		return err
+	}
+
+	return f.formatSummary(batch)
+}
+
+// BeginStream prints the table header for rows streamed with StreamRow
+// Column widths are sized from the endpoints since results are not known yet
+func (f *TableFormatter) BeginStream(endpoints []checker.Endpoint) error {
+	placeholders := make([]checker.Result, len(endpoints))
+	for i, ep := range endpoints {
+		placeholders[i] = checker.Result{Name: ep.Name, URL: ep.URL}
+	}
+	f.streamNameWidth, f.streamURLWidth = f.columnWidths(placeholders)
+
+	return f.formatRows(nil, f.streamNameWidth, f.streamURLWidth)
+}
+
+// StreamRow prints a single result row as soon as it is available
+func (f *TableFormatter) StreamRow(result checker.Result) error {
+	return f.formatRow(result, f.streamNameWidth, f.streamURLWidth)
+}
+
+// EndStream prints the summary after all streamed rows
+func (f *TableFormatter) EndStream(batch checker.BatchResult) error {
+	return f.formatSummary(batch)
+}
+
+// columnWidths calculates NAME and URL column widths within the configured limits
+func (f *TableFormatter) columnWidths(results []checker.Result) (int, int) {
 	nameWidth := 4  // "NAME"
 	urlWidth := 3   // "URL"
 
-	for _, r := range batch.Results {
+	for _, r := range results {
 		if len(r.Name) > nameWidth {
 			nameWidth = len(r.Name)
 		}
@@ -85,32 +223,18 @@ func (f *TableFormatter) FormatBatch(batch checker.BatchResult) error {
 	}
 
 	// Limit maximum width
-	if nameWidth > maxNameWidth {
-		nameWidth = maxNameWidth
+	if nameWidth > f.maxNameWidth {
+		nameWidth = f.maxNameWidth
 	}
-	if urlWidth > maxURLWidth {
-		urlWidth = maxURLWidth
+	if urlWidth > f.maxURLWidth {
+		urlWidth = f.maxURLWidth
 	}
 
-	// Print header
-	header := fmt.Sprintf("%-*s  %-*s  %-10s  %s\n",
-		nameWidth, "NAME",
-		urlWidth, "URL",
-		"STATUS",
-		"LATENCY")
-	_, err := fmt.Fprint(f.writer, header)
-	if err != nil {
-		return err
-	}
-
-	// Print each row
-	for _, result := range batch.Results {
-		if err := f.formatRow(result, nameWidth, urlWidth); err != nil {
-			return err
-		}
-	}
+	return nameWidth, urlWidth
+}
 
-	// Print summary
+// formatSummary prints the summary line and optional failure breakdown
+func (f *TableFormatter) formatSummary(batch checker.BatchResult) error {
 	fmt.Fprintln(f.writer)
 	summaryColor := colorGreen
 	if batch.Summary.Unhealthy > 0 {
@@ -121,8 +245,122 @@ func (f *TableFormatter) FormatBatch(batch checker.BatchResult) error {
 	}
 
 	summary := fmt.Sprintf("Summary: %d/%d healthy", batch.Summary.Healthy, batch.Summary.Total)
-	_, err = fmt.Fprintln(f.writer, f.colorize(summary, summaryColor))
-	return err
+	if batch.Summary.Degraded > 0 {
+		summary += fmt.Sprintf(" (%d degraded)", batch.Summary.Degraded)
+	}
+	if batch.Summary.Concurrency > 0 {
+		summary += fmt.Sprintf(" (auto concurrency: %d)", batch.Summary.Concurrency)
+	}
+	// Pointing at the bottleneck only makes sense with more than one endpoint
+	if s := batch.Summary.Slowest; s != nil && batch.Summary.Total > 1 {
+		summary += fmt.Sprintf(", slowest: %s (%s)", s.Name, formatLatency(s.Latency))
+	}
+	if _, err := fmt.Fprintln(f.writer, f.colorize(summary, summaryColor)); err != nil {
+		return err
+	}
+
+	// Print failure breakdown when requested
+	if len(batch.Summary.FailuresByKind) > 0 {
+		_, err := fmt.Fprintf(f.writer, "Failures: %s\n", formatKindCounts(batch.Summary.FailuresByKind))
+		return err
+	}
+	return nil
+}
+
+// formatKindCounts formats error kind counts as "5 dns, 3 timeout", most frequent first
+func formatKindCounts(counts map[checker.ErrorKind]int) string {
+	kinds := make([]checker.ErrorKind, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if counts[kinds[i]] != counts[kinds[j]] {
+			return counts[kinds[i]] > counts[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatRows prints the column header followed by one row per result
+func (f *TableFormatter) formatRows(results []checker.Result, nameWidth, urlWidth int) error {
+	if _, err := fmt.Fprint(f.writer, f.header(nameWidth, urlWidth, "URL")); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if err := f.formatRow(result, nameWidth, urlWidth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// header returns the column header line, titling the URL column urlTitle
+func (f *TableFormatter) header(nameWidth, urlWidth int, urlTitle string) string {
+	if f.ema {
+		return fmt.Sprintf("%-*s  %-*s  %-10s  %-*s  %s\n",
+			nameWidth, "NAME",
+			urlWidth, urlTitle,
+			"STATUS",
+			latencyWidth, "LATENCY",
+			"EMA")
+	}
+	return fmt.Sprintf("%-*s  %-*s  %-10s  %s\n",
+		nameWidth, "NAME",
+		urlWidth, urlTitle,
+		"STATUS",
+		"LATENCY")
+}
+
+// formatSections prints unhealthy results under FAILURES, then healthy ones under OK
+// Each section has its own count line; empty sections are omitted
+func (f *TableFormatter) formatSections(results []checker.Result, nameWidth, urlWidth int) error {
+	var failures, ok []checker.Result
+	for _, r := range results {
+		if r.Healthy {
+			ok = append(ok, r)
+		} else {
+			failures = append(failures, r)
+		}
+	}
+
+	sections := []struct {
+		title   string
+		color   string
+		label   string
+		results []checker.Result
+	}{
+		{"FAILURES", colorRed, "unhealthy", failures},
+		{"OK", colorGreen, "healthy", ok},
+	}
+
+	first := true
+	for _, sec := range sections {
+		if len(sec.results) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(f.writer)
+		}
+		first = false
+
+		if _, err := fmt.Fprintln(f.writer, f.colorize(sec.title, sec.color)); err != nil {
+			return err
+		}
+		if err := f.formatRows(sec.results, nameWidth, urlWidth); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(f.writer, "%d %s\n", len(sec.results), sec.label); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // formatRow formats a single row output
@@ -135,7 +373,7 @@ func (f *TableFormatter) formatRow(result checker.Result, nameWidth, urlWidth in
 	var latency string
 
 	if result.Healthy {
-		status = f.colorize("✓", colorGreen)
+		status = f.healthyMark(result)
 		if result.StatusCode != nil {
 			status += fmt.Sprintf(" %d", *result.StatusCode)
 		}
@@ -144,7 +382,7 @@ func (f *TableFormatter) formatRow(result checker.Result, nameWidth, urlWidth in
 		if result.StatusCode != nil {
 			status += fmt.Sprintf(" %d", *result.StatusCode)
 		} else if result.Error != nil {
-			status += " " + f.getShortError(result.Error)
+			status += " " + f.getShortError(result)
 		}
 	}
 
@@ -154,14 +392,143 @@ func (f *TableFormatter) formatRow(result checker.Result, nameWidth, urlWidth in
 		latency = "--"
 	}
 
-	_, err := fmt.Fprintf(f.writer, "%-*s  %-*s  %-10s  %s\n",
+	if f.ema {
+		ema := "--"
+		if result.LatencyEMA > 0 {
+			ema = formatLatency(result.LatencyEMA)
+		}
+		latency = fmt.Sprintf("%-*s  %s", latencyWidth, latency, ema)
+	}
+
+	if _, err := fmt.Fprintf(f.writer, "%-*s  %-*s  %-10s  %s\n",
 		nameWidth, name,
 		urlWidth, url,
 		status,
-		latency)
+		latency); err != nil {
+		return err
+	}
+	if err := f.formatAssertions(result.Assertions); err != nil {
+		return err
+	}
+	if err := f.formatRedirects(result.Redirects); err != nil {
+		return err
+	}
+	if err := f.formatTimings(result.Timings); err != nil {
+		return err
+	}
+	if err := f.formatDebug(result.Debug); err != nil {
+		return err
+	}
+	return f.formatWarnings(result.Warnings)
+}
+
+// formatRedirects prints the followed redirect chain, ending at the final URL, below its row in verbose mode
+func (f *TableFormatter) formatRedirects(redirects []string) error {
+	if !f.verbose || len(redirects) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(f.writer, "  redirected → %s\n", strings.Join(redirects, " → "))
 	return err
 }
 
+// formatTimings prints the connection phase breakdown indented below its row in verbose mode
+func (f *TableFormatter) formatTimings(t *checker.Timings) error {
+	if !f.verbose || t == nil {
+		return nil
+	}
+	line := fmt.Sprintf("dns %s  connect %s  tls %s  ttfb %s",
+		formatPhase(t.DNS), formatPhase(t.Connect), formatPhase(t.TLS), formatPhase(t.TTFB))
+	if t.ConnReused {
+		line += "  (reused connection)"
+	}
+	_, err := fmt.Fprintf(f.writer, "  %s\n", line)
+	return err
+}
+
+// formatDebug prints the request and response headers in curl -v style below its row in verbose mode
+func (f *TableFormatter) formatDebug(d *checker.Debug) error {
+	if !f.verbose || d == nil {
+		return nil
+	}
+	lines := []string{"> " + d.Request}
+	lines = append(lines, headerLines(">", d.RequestHeader)...)
+	if d.Status != "" {
+		lines = append(lines, "< "+d.Status)
+		lines = append(lines, headerLines("<", d.ResponseHeader)...)
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(f.writer, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerLines formats headers as "prefix Name: value" lines, Host first and the rest sorted by name
+func headerLines(prefix string, h http.Header) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "Host") != (keys[j] == "Host") {
+			return keys[i] == "Host"
+		}
+		return keys[i] < keys[j]
+	})
+
+	var lines []string
+	for _, k := range keys {
+		for _, v := range h[k] {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", prefix, k, v))
+		}
+	}
+	return lines
+}
+
+// formatPhase formats a phase duration with sub-millisecond precision, or "-" when it didn't happen
+func formatPhase(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// formatAssertions prints failed assertions indented below their row in verbose mode
+func (f *TableFormatter) formatAssertions(assertions []checker.AssertionResult) error {
+	if !f.verbose {
+		return nil
+	}
+	for _, a := range assertions {
+		if a.Passed {
+			continue
+		}
+		line := fmt.Sprintf("%s %s: %s", f.colorize("✗", colorRed), a.Type, a.Detail)
+		if _, err := fmt.Fprintf(f.writer, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatWarnings prints each warning indented below its row
+func (f *TableFormatter) formatWarnings(warnings []string) error {
+	for _, w := range warnings {
+		if _, err := fmt.Fprintf(f.writer, "  %s\n", f.colorize("⚠ "+w, colorYellow)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// healthyMark returns the status mark for a healthy result, distinguishing degraded ones
+func (f *TableFormatter) healthyMark(result checker.Result) string {
+	if result.Degraded {
+		return f.colorize("~", colorYellow)
+	}
+	return f.colorize("✓", colorGreen)
+}
+
 // colorize adds color
 func (f *TableFormatter) colorize(text, color string) string {
 	if f.noColor {
@@ -170,29 +537,31 @@ func (f *TableFormatter) colorize(text, color string) string {
 	return color + text + colorReset
 }
 
-// getShortError gets short error description
-func (f *TableFormatter) getShortError(err error) string {
-	errStr := err.Error()
+// shortErrors are the short descriptions of network failures by error kind
+var shortErrors = map[checker.ErrorKind]string{
+	checker.KindTimeout:  "timeout",
+	checker.KindRefused:  "refused",
+	checker.KindDNS:      "dns error",
+	checker.KindTLS:      "ssl error",
+	checker.KindClosed:   "conn closed",
+	checker.KindCanceled: "canceled",
+}
 
-	switch {
-	case strings.Contains(errStr, "timeout"):
-		return "timeout"
-	case strings.Contains(errStr, "connection refused"):
-		return "refused"
-	case strings.Contains(errStr, "DNS"):
-		return "dns error"
-	case strings.Contains(errStr, "certificate"):
-		return "ssl error"
-	default:
-		// Extract first part
-		if idx := strings.Index(errStr, ":"); idx > 0 && idx < 20 {
-			return errStr[:idx]
-		}
-		if len(errStr) > 15 {
-			return errStr[:15] + "..."
-		}
-		return errStr
+// getShortError gets short error description from the error kind, or the start of the message
+func (f *TableFormatter) getShortError(result checker.Result) string {
+	if short, ok := shortErrors[result.ErrorKind]; ok {
+		return short
+	}
+
+	// Extract first part
+	errStr := result.Error.Error()
+	if idx := strings.Index(errStr, ":"); idx > 0 && idx < 20 {
+		return errStr[:idx]
+	}
+	if len(errStr) > 15 {
+		return errStr[:15] + "..."
 	}
+	return errStr
 }
 
 // formatLatency formats latency time
@@ -0,0 +1,107 @@
+// Tree table view
+// Groups batch rows under their URL host with a per-host health rollup
+package output
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/r1ckyIn/healthcheck-cli/internal/checker"
+)
+
+// treeIndent prefixes endpoint rows below their host line
+const treeIndent = "  "
+
+// WithTree groups batch rows under their URL host, showing each endpoint's path
+func WithTree(enabled bool) TableOption {
+	return func(f *TableFormatter) {
+		f.tree = enabled
+	}
+}
+
+// WithCollapseHealthy reduces hosts whose endpoints are all healthy to their rollup line in tree view
+func WithCollapseHealthy(enabled bool) TableOption {
+	return func(f *TableFormatter) {
+		f.collapseHealthy = enabled
+	}
+}
+
+// hostGroup is the results of one host in tree view
+type hostGroup struct {
+	host    string
+	healthy int
+	rows    []checker.Result
+}
+
+// formatTree prints one rollup line per host followed by its indented endpoint rows
+// Hosts appear in the order of their first endpoint; rows show the path instead of the full URL
+func (f *TableFormatter) formatTree(results []checker.Result) error {
+	var groups []*hostGroup
+	byHost := make(map[string]*hostGroup)
+	var rows []checker.Result
+	for _, r := range results {
+		host, path := splitHost(r.URL)
+		g, ok := byHost[host]
+		if !ok {
+			g = &hostGroup{host: host}
+			byHost[host] = g
+			groups = append(groups, g)
+		}
+		if r.Healthy {
+			g.healthy++
+		}
+		r.Name = treeIndent + r.Name
+		r.URL = path
+		g.rows = append(g.rows, r)
+		rows = append(rows, r)
+	}
+
+	nameWidth, urlWidth := f.columnWidths(rows)
+	if _, err := fmt.Fprint(f.writer, f.header(nameWidth, urlWidth, "PATH")); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		collapsed := f.collapseHealthy && g.healthy == len(g.rows)
+
+		marker, color := "▾", colorYellow
+		if collapsed {
+			marker = "▸"
+		}
+		switch g.healthy {
+		case len(g.rows):
+			color = colorGreen
+		case 0:
+			color = colorRed
+		}
+		line := fmt.Sprintf("%s %s  %d/%d healthy", marker, g.host, g.healthy, len(g.rows))
+		if _, err := fmt.Fprintln(f.writer, f.colorize(line, color)); err != nil {
+			return err
+		}
+		if collapsed {
+			continue
+		}
+
+		for _, r := range g.rows {
+			if err := f.formatRow(r, nameWidth, urlWidth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitHost splits a URL into its host and the rest as written, e.g. "/v1/health?x=1"
+// URLs without a host are grouped under the URL itself
+func splitHost(rawURL string) (string, string) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL, "/"
+	}
+	_, rest, _ := strings.Cut(rawURL, u.Host)
+	if rest == "" {
+		rest = "/"
+	}
+	return u.Host, rest
+}
@@ -0,0 +1,84 @@
+// Schedule module unit tests
+// Test cron expression parsing and next-run calculation
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParse_Invalid tests rejected cron expressions
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		expr     string
+		contains string
+	}{
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "minute: value 60 out of range"},
+		{"* 24 * * *", "hour: value 24 out of range"},
+		{"* * 0 * *", "day of month: value 0 out of range"},
+		{"*/0 * * * *", "minute: invalid step"},
+		{"a * * * *", "minute: invalid value"},
+		{"10-5 * * * *", "minute: invalid range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse(%q) error = nil, want error", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Parse(%q) error = %q, want to contain %q", tt.expr, err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+// TestSchedule_Next tests next-run calculation
+func TestSchedule_Next(t *testing.T) {
+	// Monday, 15 January 2024 10:07:30 UTC
+	base := time.Date(2024, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2024, 1, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2024, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 5", time.Date(2024, 1, 19, 12, 0, 0, 0, time.UTC)},
+		{"15,45 8-18/2 * * *", time.Date(2024, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (1st of month or Wednesday)
+		{"0 0 1 * 3", time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+		// A field starting with * counts as unrestricted: both must match (odd day and Monday)
+		{"0 0 */2 * 1", time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Next(base); !got.Equal(tt.expected) {
+				t.Errorf("Next() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSchedule_NextNoMatch tests an expression that never matches
+func TestSchedule_NextNoMatch(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}